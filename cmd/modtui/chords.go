@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/slimewell/GoMod/internal/ui"
+)
+
+// runChords implements `gomod chords <file|->`
+// It prints a chord chart (one line per bar) with key estimates, from the song's pattern data
+func runChords(args []string) int {
+	fs := flag.NewFlagSet("chords", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gomod chords <file|->\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	mod, err := loadModuleArg(fs.Arg(0))
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	defer mod.Close()
+
+	orders := mod.GetOrderList()
+	roll := ui.BuildPianoRoll(mod, orders, mod.GetNumChannels())
+	for _, line := range ui.ChordChartLines(roll, ui.BuildHarmony(roll), orders) {
+		fmt.Println(line)
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+	"github.com/slimewell/GoMod/internal/ui"
+)
+
+// runDump implements `gomod dump [flags] <file|->`
+// It prints pattern data as text for pasting into trackers, forum posts and docs
+func runDump(args []string) int {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	formatName := fs.String("format", "ascii", "Text format: ascii (like the pattern view) or openmpt (clipboard format)")
+	pattern := fs.Int("pattern", -1, "Pattern to dump (default: every pattern in the song, in order list order)")
+	rows := fs.String("rows", "", "Row range, e.g. 16-31 or 0x10-0x1F (default: all rows)")
+	channels := fs.String("channels", "", "Channel range, 1-based, e.g. 1-4 (default: all channels)")
+	densityName := fs.String("density", "full", "ASCII cell width: full, compact or notes")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gomod dump [flags] <file|->\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	format, err := ui.ParseDumpFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	density, err := ui.ParsePatternDensity(*densityName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	rng := ui.WholePattern
+	if *rows != "" {
+		if rng.FirstRow, rng.LastRow, err = parseRange(*rows); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -rows: %v\n", err)
+			return 2
+		}
+	}
+	if *channels != "" {
+		first, last, err := parseRange(*channels)
+		if err != nil || first < 1 {
+			fmt.Fprintf(os.Stderr, "Error: -channels: channels are numbered from 1\n")
+			return 2
+		}
+		rng.FirstChannel, rng.LastChannel = first-1, last-1
+	}
+
+	mod, err := loadModuleArg(positional[0])
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	defer mod.Close()
+
+	if *pattern >= 0 {
+		if *pattern >= mod.GetNumPatterns() || mod.GetPatternNumRows(*pattern) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: pattern %d does not exist\n", *pattern)
+			return 1
+		}
+		fmt.Print(ui.DumpPattern(mod, *pattern, rng, format, density))
+		return 0
+	}
+
+	// Whole song: each pattern once, where the order list first reaches it, headed by every order that plays it
+	orders := mod.GetOrderList()
+	playedAt := make(map[int][]string)
+	var patterns []int
+	for order, p := range orders {
+		if p == player.OrderSkip || p == player.OrderEnd {
+			continue // Later subsongs sit past "---" markers
+		}
+		if playedAt[p] == nil {
+			patterns = append(patterns, p)
+		}
+		playedAt[p] = append(playedAt[p], fmt.Sprintf("%03d", order))
+	}
+
+	for i, p := range patterns {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("Pattern %03d (orders %s)\n", p, strings.Join(playedAt[p], ", "))
+		fmt.Print(ui.DumpPattern(mod, p, rng, format, density))
+	}
+	return 0
+}
+
+// parseRange reads "a-b" or a single number; numbers may be decimal or 0x hex
+func parseRange(s string) (int, int, error) {
+	firstText, lastText, isRange := strings.Cut(s, "-")
+	first, err := strconv.ParseInt(strings.TrimSpace(firstText), 0, 32)
+	if err != nil || first < 0 {
+		return 0, 0, fmt.Errorf("%q is not a range like 0-15", s)
+	}
+	if !isRange {
+		return int(first), int(first), nil
+	}
+	last, err := strconv.ParseInt(strings.TrimSpace(lastText), 0, 32)
+	if err != nil || last < first {
+		return 0, 0, fmt.Errorf("%q is not a range like 0-15", s)
+	}
+	return int(first), int(last), nil
+}
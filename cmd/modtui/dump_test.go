@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input       string
+		first, last int
+		wantErr     bool
+	}{
+		{"16-31", 16, 31, false},
+		{"0x10-0x1F", 16, 31, false},
+		{"0x10-31", 16, 31, false},
+		{"5", 5, 5, false},
+		{"0", 0, 0, false},
+		{" 1 - 4 ", 1, 4, false},
+		{"7-7", 7, 7, false},
+		{"", 0, 0, true},
+		{"3-1", 0, 0, true},
+		{"-1", 0, 0, true},
+		{"1-", 0, 0, true},
+		{"a-b", 0, 0, true},
+		{"1-2-3", 0, 0, true},
+	}
+	for _, tt := range tests {
+		first, last, err := parseRange(tt.input)
+		if (err != nil) != tt.wantErr || first != tt.first || last != tt.last {
+			t.Errorf("parseRange(%q) = %d, %d, %v; want %d, %d, error %v", tt.input, first, last, err, tt.first, tt.last, tt.wantErr)
+		}
+	}
+}
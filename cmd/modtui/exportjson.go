@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/slimewell/GoMod/export"
+	"github.com/slimewell/GoMod/internal/ui"
+)
+
+// runExportJSON implements `gomod export-json [flags] <file|->`
+// It writes the full song structure (see export.Song) without opening the audio device
+func runExportJSON(args []string) int {
+	fs := flag.NewFlagSet("export-json", flag.ContinueOnError)
+	output := fs.String("o", "-", "Output JSON file (- for stdout)")
+	text := fs.Bool("text", false, "Include each cell's tracker text (\"C-5 01 v40 A0F\")")
+	compact := fs.Bool("compact", false, "Write JSON without indentation")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gomod export-json [flags] <file|->\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	opts := export.SongOptions{Text: *text}
+	var song export.Song
+	if positional[0] == ui.StdinName {
+		song, err = export.ReadSong(os.Stdin, opts)
+	} else {
+		song, err = export.LoadSong(positional[0], opts)
+	}
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	out := os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := export.WriteSongJSON(out, song, *compact); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
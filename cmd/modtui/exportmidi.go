@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/slimewell/GoMod/internal/midi"
+)
+
+// runExportMIDI implements `gomod export-midi [flags] <file|-> -o out.mid`
+// It renders the song silently to follow playback order and writes a Standard MIDI File
+func runExportMIDI(args []string) int {
+	fs := flag.NewFlagSet("export-midi", flag.ContinueOnError)
+	output := fs.String("o", "", "Output MIDI file (- for stdout)")
+	tracks := fs.String("tracks", "channel", "One MIDI track per \"channel\" or per \"instrument\"")
+	mapFile := fs.String("map", "", "Instrument to GM program mapping file (JSON)")
+	writeMap := fs.String("write-map", "", "Write this module's instrument mapping to a JSON file for editing")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gomod export-midi [flags] <file|-> -o out.mid\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 || (*output == "" && *writeMap == "") {
+		fs.Usage()
+		return 2
+	}
+
+	mode, err := midi.ParseTrackMode(*tracks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	gm := midi.DefaultGMMap()
+	if *mapFile != "" {
+		if gm, err = midi.LoadGMMap(*mapFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	mod, err := loadModuleArg(positional[0])
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	defer mod.Close()
+
+	if *writeMap != "" {
+		names := mod.GetRawInstrumentNames()
+		if len(names) == 0 {
+			names = mod.GetRawSampleNames()
+		}
+		if err := midi.WriteGMMap(*writeMap, gm, names); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	if *output == "" {
+		return 0
+	}
+
+	out := os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := midi.Export(mod, out, midi.Options{
+		Tracks: mode,
+		GM:     gm,
+		Warn:   func(msg string) { fmt.Fprintf(os.Stderr, "Warning: %s\n", msg) },
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+// runInfo implements `gomod info [--json] <file|->`
+// It loads the module without opening the audio device and prints what libopenmpt knows
+func runInfo(args []string) int {
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print machine-readable JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gomod info [--json] <file|->\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	mod, err := loadModuleArg(fs.Arg(0))
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	defer mod.Close()
+
+	info := mod.GetInfo()
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(info); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	printInfo(os.Stdout, info)
+	return 0
+}
+
+// printInfo writes the human-readable report
+func printInfo(w io.Writer, info player.ModuleInfo) {
+	fmt.Fprintln(w, "Metadata:")
+	for _, key := range info.MetadataKeys {
+		value := info.Metadata[key]
+		if value == "" {
+			continue
+		}
+		// Multi-line values (message, warnings) are indented under their key
+		lines := strings.Split(strings.TrimRight(value, "\n"), "\n")
+		fmt.Fprintf(w, "  %-16s %s\n", key+":", lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintf(w, "  %-16s %s\n", "", line)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Duration:         %s\n", formatDuration(info.Duration))
+	fmt.Fprintf(w, "Initial speed:    %d\n", info.InitialSpeed)
+	fmt.Fprintf(w, "Initial tempo:    %d\n", info.InitialTempo)
+	fmt.Fprintf(w, "Channels:         %d\n", info.NumChannels)
+	fmt.Fprintf(w, "Patterns:         %d\n", info.NumPatterns)
+	fmt.Fprintf(w, "Orders:           %d\n", info.NumOrders)
+	fmt.Fprintf(w, "Instruments:      %d\n", info.NumInstruments)
+	fmt.Fprintf(w, "Samples:          %d\n", info.NumSamples)
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Subsongs (%d):\n", len(info.Subsongs))
+	for _, s := range info.Subsongs {
+		fmt.Fprintf(w, "  %2d  %8s  %s\n", s.Index, formatDuration(s.Duration), s.Name)
+	}
+
+	// Trackers number patterns and orders from 0, everything else from 1
+	printNames(w, "Channel names", info.ChannelNames, 1, "%02d")
+	printNames(w, "Pattern names", info.PatternNames, 0, "%03d")
+	printNames(w, "Order names", info.OrderNames, 0, "%03d")
+	printNames(w, "Instrument names", info.InstrumentNames, 1, "%02X")
+	printNames(w, "Sample names", info.SampleNames, 1, "%02X")
+}
+
+// printNames lists non-empty names with their index, starting at base and printed with indexFormat
+// (decimal for channels, orders and patterns as elsewhere, hex for instruments and samples)
+// Sections where every name is blank are skipped entirely
+func printNames(w io.Writer, title string, names []string, base int, indexFormat string) {
+	hasAny := false
+	for _, name := range names {
+		if strings.TrimSpace(name) != "" {
+			hasAny = true
+			break
+		}
+	}
+	if !hasAny {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s:\n", title)
+	for i, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		fmt.Fprintf(w, "  "+indexFormat+"  %s\n", i+base, name)
+	}
+}
+
+func formatDuration(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+	minutes := int(d.Minutes())
+	secs := d.Seconds() - float64(minutes*60)
+	return fmt.Sprintf("%d:%06.3f", minutes, secs)
+}
@@ -1,16 +1,38 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 
+	"github.com/slimewell/GoMod/internal/player"
 	"github.com/slimewell/GoMod/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	// Headless subcommands never touch the audio device or the config file
+	// A module whose name matches a subcommand plays as "gomod -- info" or "gomod ./info";
+	// flag.Parse below drops the "--"
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "info":
+			os.Exit(runInfo(os.Args[2:]))
+		case "stats":
+			os.Exit(runStats(os.Args[2:]))
+		case "export-midi":
+			os.Exit(runExportMIDI(os.Args[2:]))
+		case "dump":
+			os.Exit(runDump(os.Args[2:]))
+		case "export-json":
+			os.Exit(runExportJSON(os.Args[2:]))
+		case "chords":
+			os.Exit(runChords(os.Args[2:]))
+		}
+	}
+
 	// Load saved config
 	cfg, err := ui.LoadConfig()
 	if err != nil {
@@ -23,16 +45,30 @@ func main() {
 	stereoSep := flag.Int("separation", cfg.StereoSep, "Stereo separation percentage (0-100)")
 	theme := flag.String("theme", cfg.Theme, "Color theme")
 	flag.StringVar(theme, "t", cfg.Theme, "Color theme (shorthand)")
+	bands := flag.Int("bands", cfg.SpectrumBands, "Spectrum analyzer band count (4-128, 0 = default)")
+	loops := flag.Int("loops", 0, "Fade out after the song has played this many times (0 = stop at its end, -1 = loop forever; not saved)")
+	maxDuration := flag.Duration("max-duration", 0, "Fade out so playback ends after this long, e.g. 10m (0 = no limit; not saved)")
+	fade := flag.Duration("fade", cfg.FadeLength(), "Fade-out length for -loops, -max-duration and the sleep timer (0 = no fade)")
+	sleep := flag.Duration("sleep", 0, "Sleep timer: fade out and stop after this long, e.g. 30m (not saved)")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "Usage: gomod [flags] [file|-]\n")
+		fmt.Fprintf(out, "       gomod <subcommand> [flags] <file|->\n")
+		fmt.Fprintf(out, "Use \"gomod -- <file>\" or a ./ path to play a file named like a subcommand.\n")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	filename := ""
 	if flag.NArg() > 0 {
 		filename = flag.Arg(0)
 
-		// Validate file exists
-		if _, err := os.Stat(filename); os.IsNotExist(err) {
-			fmt.Fprintf(os.Stderr, "Error: File not found: %s\n", filename)
-			os.Exit(1)
+		// Validate file exists ("-" reads the module from stdin)
+		if filename != ui.StdinName {
+			if _, err := os.Stat(filename); os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Error: File not found: %s\n", filename)
+				os.Exit(1)
+			}
 		}
 	}
 
@@ -42,28 +78,92 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *loops < -1 || *maxDuration < 0 || *fade < 0 || *sleep < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -loops must be -1 or more, and durations can't be negative\n")
+		os.Exit(1)
+	}
+
 	// Save config for next time (only updates startup args, not dynamic file loads yet)
 	cfg.Theme = *theme
 	cfg.StereoSep = *stereoSep
-	if filename != "" {
+	cfg.SpectrumBands = *bands
+	fadeMs := int(fade.Milliseconds())
+	cfg.FadeMs = &fadeMs
+	if filename != "" && filename != ui.StdinName {
 		cfg.LastUsed = filename
 	}
 	if err := ui.SaveConfig(cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to save config: %v\n", err)
 	}
+	cfg.Loops = *loops
+	cfg.MaxDuration = *maxDuration
+	cfg.Sleep = *sleep
 
 	// Create and run the TUI
-	// NewModel now handles empty filename by opening the browser
-	model, err := ui.NewModel(filename, *stereoSep, *theme)
+	// NewModel handles a zero source by opening the browser
+	var source ui.ModuleSource
+	if filename != "" {
+		source = ui.SourceFromArg(filename)
+	}
+	model, err := ui.NewModel(source, *cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing audio: %v\n", err)
 		os.Exit(1)
 	}
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	// Mouse reporting is turned on by the player only while the minimap is shown
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if filename == ui.StdinName {
+		// stdin carries the module data, so read keys from the terminal instead
+		opts = append(opts, tea.WithInputTTY())
+	}
+	p := tea.NewProgram(model, opts...)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+
+	// The alt screen is gone now, so repeat load failures where they stay visible
+	if app, ok := final.(ui.AppModel); ok && app.Err() != nil {
+		printError(app.Err())
+		os.Exit(1)
+	}
+}
+
+// loadModuleArg loads a module for the headless subcommands ("-" reads stdin)
+func loadModuleArg(arg string) (*player.Module, error) {
+	return ui.SourceFromArg(arg).Load()
+}
+
+// parseInterspersed parses flags that may come before or after the positional arguments
+// ("in.it -o out.mid"), returning the positional arguments in order
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// printError reports an error on stderr, including libopenmpt's diagnostics for load failures
+func printError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+	var loadErr *player.LoadError
+	if errors.As(err, &loadErr) {
+		if loadErr.Code != 0 {
+			fmt.Fprintf(os.Stderr, "  libopenmpt error code: %d\n", loadErr.Code)
+		}
+		for _, line := range loadErr.Log {
+			fmt.Fprintf(os.Stderr, "  %s\n", line)
+		}
+	}
 }
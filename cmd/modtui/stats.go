@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/slimewell/GoMod/internal/ui"
+)
+
+// runStats implements `gomod stats [--json] <file|->`
+// It walks the song's pattern data without opening the audio device
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print machine-readable JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gomod stats [--json] <file|->\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	mod, err := loadModuleArg(fs.Arg(0))
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	defer mod.Close()
+
+	stats := mod.GetStats()
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(stats); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, line := range ui.StatsLines(stats) {
+		fmt.Println(line)
+	}
+	return 0
+}
@@ -0,0 +1,167 @@
+// Package export reads tracker modules into plain Go structs, for analysis tools
+// It is the public face of GoMod's libopenmpt bindings: programs that import it need
+// libopenmpt installed to build, but no cgo code of their own.
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+// SongSchemaVersion is bumped whenever a field of Song changes meaning or is removed
+// Adding fields does not bump it, so readers should ignore keys they don't know.
+const SongSchemaVersion = 1
+
+// Song is the full structure of a module, for analysis without libopenmpt
+type Song struct {
+	SchemaVersion int `json:"schema_version"`
+
+	Metadata     map[string]string `json:"metadata"` // Every libopenmpt metadata key ("title", "type", "tracker", ...)
+	Duration     float64           `json:"duration_seconds"`
+	InitialSpeed int               `json:"initial_speed"`
+	InitialTempo int               `json:"initial_tempo"`
+	Subsongs     []Subsong         `json:"subsongs"`
+
+	NumChannels  int      `json:"num_channels"`
+	ChannelNames []string `json:"channel_names"`
+
+	// Orders lists the pattern played at each order position
+	// 65534 is a "+++" skip marker and 65535 the "---" end marker. An order naming a pattern
+	// the module doesn't have is also reported as 65535, since playback stops there too.
+	Orders     []int    `json:"orders"`
+	OrderNames []string `json:"order_names"`
+
+	Patterns []SongPattern `json:"patterns"` // Indexed by pattern number; unused slots have no rows
+
+	InstrumentNames []string `json:"instrument_names"` // Instrument 1 is index 0
+	SampleNames     []string `json:"sample_names"`     // Sample 1 is index 0
+}
+
+// Subsong is one of the module's subsongs (most modules have just one)
+type Subsong struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Duration float64 `json:"duration_seconds"`
+}
+
+// SongPattern is one pattern's cells, Rows[row][channel]
+type SongPattern struct {
+	Index int          `json:"index"`
+	Name  string       `json:"name,omitempty"`
+	Rows  [][]SongCell `json:"rows"`
+}
+
+// SongCell holds the six raw libopenmpt commands of a cell; zero fields are omitted
+// Note is 1-120 for C-0 to B-9, 253 note fade, 254 note cut, 255 key off. Effect and
+// VolumeEffect use OpenMPT's internal command numbers, which are the same for every format.
+type SongCell struct {
+	Note         int    `json:"note,omitempty"`
+	Instrument   int    `json:"instrument,omitempty"`
+	VolumeEffect int    `json:"volume_effect,omitempty"`
+	Volume       int    `json:"volume,omitempty"`
+	Effect       int    `json:"effect,omitempty"`
+	Parameter    int    `json:"parameter,omitempty"`
+	Text         string `json:"text,omitempty"` // The cell as the original tracker shows it, with SongOptions.Text
+}
+
+// SongOptions configures LoadSong and ReadSong
+type SongOptions struct {
+	Text bool // Include each cell's tracker text ("C-5 01 v40 A0F"); roughly triples the size
+}
+
+// LoadError describes why libopenmpt refused a module; LoadSong and ReadSong return it
+// as a *LoadError. Match its Kind with errors.Is against the Err values below.
+type LoadError = player.LoadError
+
+// Load error kinds
+var (
+	ErrUnsupportedFormat = player.ErrUnsupportedFormat
+	ErrCorrupt           = player.ErrCorrupt
+	ErrOutOfMemory       = player.ErrOutOfMemory
+)
+
+// LoadSong reads the module file at path into a Song
+// Errors are a *LoadError, or an I/O error if the file can't be read.
+func LoadSong(path string, opts SongOptions) (Song, error) {
+	mod, err := player.LoadModule(path)
+	if err != nil {
+		return Song{}, err
+	}
+	defer mod.Close()
+	return songFromModule(mod, opts), nil
+}
+
+// ReadSong reads a module from r (to EOF) into a Song
+// Errors are as for LoadSong.
+func ReadSong(r io.Reader, opts SongOptions) (Song, error) {
+	mod, err := player.LoadModuleFromReader(r)
+	if err != nil {
+		return Song{}, err
+	}
+	defer mod.Close()
+	return songFromModule(mod, opts), nil
+}
+
+// songFromModule collects the song structure from the module's accessors
+// It rewinds playback (GetInfo measures subsongs), so it must not run while the module plays.
+func songFromModule(mod *player.Module, opts SongOptions) Song {
+	info := mod.GetInfo()
+
+	song := Song{
+		SchemaVersion:   SongSchemaVersion,
+		Metadata:        info.Metadata,
+		Duration:        info.Duration,
+		InitialSpeed:    info.InitialSpeed,
+		InitialTempo:    info.InitialTempo,
+		Subsongs:        make([]Subsong, len(info.Subsongs)),
+		NumChannels:     info.NumChannels,
+		ChannelNames:    info.ChannelNames,
+		Orders:          mod.GetOrderList(),
+		OrderNames:      info.OrderNames,
+		Patterns:        make([]SongPattern, info.NumPatterns),
+		InstrumentNames: info.InstrumentNames,
+		SampleNames:     info.SampleNames,
+	}
+
+	for i, sub := range info.Subsongs {
+		song.Subsongs[i] = Subsong{Index: sub.Index, Name: sub.Name, Duration: sub.Duration}
+	}
+
+	for i := range song.Patterns {
+		pattern := SongPattern{Index: i, Rows: [][]SongCell{}}
+		if i < len(info.PatternNames) {
+			pattern.Name = info.PatternNames[i]
+		}
+		for _, row := range mod.GetCachedPattern(i).Rows {
+			cells := make([]SongCell, len(row.Channels))
+			for ch, cell := range row.Channels {
+				cells[ch] = SongCell{
+					Note:         cell.Note,
+					Instrument:   cell.Instrument,
+					VolumeEffect: cell.VolumeEffect,
+					Volume:       cell.Volume,
+					Effect:       cell.Effect,
+					Parameter:    cell.Parameter,
+				}
+				if opts.Text {
+					cells[ch].Text = cell.Text
+				}
+			}
+			pattern.Rows = append(pattern.Rows, cells)
+		}
+		song.Patterns[i] = pattern
+	}
+
+	return song
+}
+
+// WriteSongJSON writes the song as JSON, indented unless compact is set
+func WriteSongJSON(w io.Writer, song Song, compact bool) error {
+	enc := json.NewEncoder(w)
+	if !compact {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(song)
+}
@@ -0,0 +1,153 @@
+package midi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GMMapping says how one module instrument plays back on a General MIDI synth
+type GMMapping struct {
+	Name      string `json:"name,omitempty"` // Informational, written by WriteGMMap so the file is readable
+	Program   int    `json:"program"`        // GM program, 0-127 (0 = Acoustic Grand Piano)
+	Drum      bool   `json:"drum,omitempty"` // Play on the GM percussion channel
+	Key       int    `json:"key,omitempty"`  // Fixed percussion key for drums (0 = follow the pattern's notes)
+	Transpose int    `json:"transpose,omitempty"`
+}
+
+// GMMap maps module instruments to GM programs
+// Lookup order: Instruments (by the hex number shown in patterns), then the first Names keyword
+// found in the instrument name (longest keywords first), then Default.
+type GMMap struct {
+	Default     GMMapping            `json:"default"`
+	Instruments map[string]GMMapping `json:"instruments,omitempty"`
+	Names       map[string]GMMapping `json:"names,omitempty"`
+}
+
+// DefaultGMMap guesses programs from common instrument naming habits
+func DefaultGMMap() GMMap {
+	return GMMap{
+		Default: GMMapping{Program: 0},
+		Names: map[string]GMMapping{
+			"piano":    {Program: 0},
+			"epiano":   {Program: 4},
+			"rhodes":   {Program: 4},
+			"organ":    {Program: 16},
+			"guitar":   {Program: 25},
+			"gtr":      {Program: 25},
+			"dist":     {Program: 30},
+			"bass":     {Program: 33},
+			"slap":     {Program: 36},
+			"string":   {Program: 48},
+			"choir":    {Program: 52},
+			"voice":    {Program: 54},
+			"vox":      {Program: 54},
+			"brass":    {Program: 61},
+			"trumpet":  {Program: 56},
+			"sax":      {Program: 65},
+			"flute":    {Program: 73},
+			"lead":     {Program: 80},
+			"saw":      {Program: 81},
+			"pad":      {Program: 88},
+			"bell":     {Program: 14},
+			"kick":     {Drum: true, Key: 36},
+			"bd":       {Drum: true, Key: 36},
+			"bassdrum": {Drum: true, Key: 36},
+			"snare":    {Drum: true, Key: 38},
+			"sd":       {Drum: true, Key: 38},
+			"clap":     {Drum: true, Key: 39},
+			"tom":      {Drum: true, Key: 45},
+			"hihat":    {Drum: true, Key: 42},
+			"hat":      {Drum: true, Key: 42},
+			"hh":       {Drum: true, Key: 42},
+			"crash":    {Drum: true, Key: 49},
+			"ride":     {Drum: true, Key: 51},
+			"cymbal":   {Drum: true, Key: 49},
+			"perc":     {Drum: true, Key: 0},
+		},
+	}
+}
+
+// LoadGMMap reads a mapping file on top of DefaultGMMap, so a file only needs the entries it changes
+func LoadGMMap(path string) (GMMap, error) {
+	gm := DefaultGMMap()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return gm, err
+	}
+	if err := json.Unmarshal(data, &gm); err != nil {
+		return gm, fmt.Errorf("%s: %w", path, err)
+	}
+	for key := range gm.Instruments {
+		if _, err := parseInstrumentKey(key); err != nil {
+			return gm, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return gm, nil
+}
+
+// WriteGMMap saves the resolved mapping of every instrument, ready for hand editing
+func WriteGMMap(path string, gm GMMap, names []string) error {
+	out := GMMap{Default: gm.Default, Instruments: make(map[string]GMMapping)}
+	for i, name := range names {
+		mapping := gm.Lookup(i+1, name)
+		mapping.Name = strings.TrimSpace(name)
+		out.Instruments[fmt.Sprintf("%02X", i+1)] = mapping
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Lookup resolves the mapping for a 1-based instrument number and its name
+func (gm GMMap) Lookup(instrument int, name string) GMMapping {
+	for key, mapping := range gm.Instruments {
+		if n, err := parseInstrumentKey(key); err == nil && n == instrument {
+			return mapping
+		}
+	}
+
+	// Keywords match whole words first ("bd" shouldn't hit "abduction"), then anywhere
+	lower := strings.ToLower(name)
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	keys := make([]string, 0, len(gm.Names))
+	for key := range gm.Names {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		for _, word := range words {
+			if word == key {
+				return gm.Names[key]
+			}
+		}
+	}
+	for _, key := range keys {
+		// Short abbreviations only count as whole words
+		if len(key) > 3 && strings.Contains(lower, key) {
+			return gm.Names[key]
+		}
+	}
+	return gm.Default
+}
+
+// parseInstrumentKey reads an Instruments key: hex as shown in patterns ("0A"), 01-FF
+func parseInstrumentKey(key string) (int, error) {
+	n, err := strconv.ParseUint(key, 16, 8)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("instrument %q is not a hex number 01-FF", key)
+	}
+	return int(n), nil
+}
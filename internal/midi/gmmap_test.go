@@ -0,0 +1,57 @@
+package midi
+
+import "testing"
+
+func TestGMMapLookup(t *testing.T) {
+	gm := DefaultGMMap()
+	gm.Instruments = map[string]GMMapping{"0a": {Program: 40}}
+
+	tests := []struct {
+		instrument int
+		name       string
+		want       GMMapping
+	}{
+		{10, "Fat Bass", GMMapping{Program: 40}}, // Instrument number wins over the name
+		{11, "Fat Bass", GMMapping{Program: 33}},
+		{1, "kick", GMMapping{Drum: true, Key: 36}},
+		{1, "BD hard", GMMapping{Drum: true, Key: 36}},
+		{1, "bassdrum 1", GMMapping{Drum: true, Key: 36}}, // Longest keyword first
+		{1, "hh open", GMMapping{Drum: true, Key: 42}},
+		{1, "distorted gtr", GMMapping{Program: 25}}, // Whole words beat substrings ("dist")
+		{1, "Strings", GMMapping{Program: 48}},       // Substring of a long keyword
+		{1, "abduction", GMMapping{Program: 0}},      // "bd" only counts as a whole word
+		{1, "hard sd.wav", GMMapping{Drum: true, Key: 38}},
+		{1, "synth", GMMapping{Program: 0}},
+		{1, "", GMMapping{Program: 0}},
+	}
+	for _, tt := range tests {
+		if got := gm.Lookup(tt.instrument, tt.name); got != tt.want {
+			t.Errorf("Lookup(%d, %q) = %+v, want %+v", tt.instrument, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseInstrumentKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    int
+		wantErr bool
+	}{
+		{"01", 1, false},
+		{"1", 1, false},
+		{"0A", 10, false},
+		{"0a", 10, false},
+		{"FF", 255, false},
+		{"00", 0, true},
+		{"100", 0, true},
+		{"zz", 0, true},
+		{"-1", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseInstrumentKey(tt.key)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseInstrumentKey(%q) = %d, %v; want %d, error %v", tt.key, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
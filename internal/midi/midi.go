@@ -0,0 +1,408 @@
+package midi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+const (
+	// midiPPQ is ticks per quarter note; rows land on a fixed grid of midiPPQ/midiRowsPerBeat ticks
+	midiPPQ         = 480
+	midiRowsPerBeat = 4
+
+	// midiDrumChannel is the GM percussion channel (10, zero-based 9)
+	midiDrumChannel = 9
+	// midiMelodicChannels is every channel but the drum channel
+	midiMelodicChannels = 15
+
+	// midiDefaultVelocity is used when a note has no volume command
+	midiDefaultVelocity = 100
+
+	// midiDefaultSpeed places note cuts when a row's speed is unknown
+	midiDefaultSpeed = 6
+
+	// midiTempoTolerance is how far a row's length can drift before a new tempo event is written
+	midiTempoTolerance = 0.005
+
+	// midiMeasuredTolerance is how far a row's measured length can differ from speed/tempo
+	// before the measurement wins (pattern delays, fine tempo modes)
+	midiMeasuredTolerance = 0.05
+)
+
+// TrackMode chooses how module data is split into MIDI tracks
+type TrackMode int
+
+const (
+	TracksPerChannel    TrackMode = iota // One track per module channel
+	TracksPerInstrument                  // One track per module instrument
+)
+
+// ParseTrackMode understands "channel" and "instrument"
+func ParseTrackMode(s string) (TrackMode, error) {
+	switch s {
+	case "channel", "channels":
+		return TracksPerChannel, nil
+	case "instrument", "instruments":
+		return TracksPerInstrument, nil
+	}
+	return TracksPerChannel, fmt.Errorf("unknown track mode %q (want channel or instrument)", s)
+}
+
+// Options configures Export
+type Options struct {
+	Tracks TrackMode
+	GM     GMMap
+	Warn   func(msg string) // Optional; told when the export has to compromise (e.g. shared MIDI channels)
+}
+
+// midiEvent is one event at an absolute tick
+type midiEvent struct {
+	tick int
+	rank int // Orders events on the same tick: note-offs, then setup, then note-ons
+	data []byte
+}
+
+const (
+	rankNoteOff = iota
+	rankSetup
+	rankNoteOn
+)
+
+// midiTrack collects events for one MTrk chunk
+type midiTrack struct {
+	name   string
+	events []midiEvent
+}
+
+func (t *midiTrack) add(tick, rank int, data ...byte) {
+	t.events = append(t.events, midiEvent{tick: tick, rank: rank, data: data})
+}
+
+func (t *midiTrack) meta(tick, kind int, payload []byte) {
+	data := append([]byte{0xFF, byte(kind)}, appendVLQ(nil, len(payload))...)
+	t.add(tick, rankSetup, append(data, payload...)...)
+}
+
+// activeNote is a sounding MIDI note started by a module channel
+type activeNote struct {
+	track   *midiTrack
+	channel byte
+	key     byte
+}
+
+// Export plays the song silently and writes it as a type-1 Standard MIDI File
+// Rows are placed on a sixteenth-note grid and tempo events keep them at their real times, so
+// speed and tempo changes, pattern breaks, jumps and loops come out as the song actually plays.
+// Note-offs, cuts (including ECx/SCx), fades and zero volumes end notes, and velocity comes from
+// the volume column or a set-volume effect. Module channels mapped to the same GM program share a
+// MIDI channel. Samples, slides and other effects are not translated.
+func Export(mod *player.Module, w io.Writer, opts Options) error {
+	rows, length := mod.Timeline()
+	if len(rows) == 0 {
+		return fmt.Errorf("song has no rows to export")
+	}
+
+	numChannels := mod.GetNumChannels()
+	names := mod.GetRawInstrumentNames()
+	if len(names) == 0 {
+		names = mod.GetRawSampleNames()
+	}
+	lookup := func(instrument int) GMMapping {
+		name := ""
+		if instrument >= 1 && instrument <= len(names) {
+			name = names[instrument-1]
+		}
+		return opts.GM.Lookup(instrument, name)
+	}
+
+	conductor := &midiTrack{name: mod.GetMetadata().Title}
+	ticksPerRow := midiPPQ / midiRowsPerBeat
+
+	// Tempo map: one event whenever the row length changes noticeably
+	lastTempo := 0
+	for i, row := range rows {
+		end := length
+		if i+1 < len(rows) {
+			end = rows[i+1].Start
+		}
+		seconds := rowSeconds(row, end-row.Start)
+		if seconds <= 0 {
+			continue
+		}
+		tempo := min(int(math.Round(seconds*midiRowsPerBeat*1e6)), 0xFFFFFF)
+		if lastTempo == 0 || math.Abs(float64(tempo-lastTempo)) > float64(lastTempo)*midiTempoTolerance {
+			conductor.meta(i*ticksPerRow, 0x51, []byte{byte(tempo >> 16), byte(tempo >> 8), byte(tempo)})
+			lastTempo = tempo
+		}
+	}
+
+	// Tracks are created on first use so silent channels and unused instruments don't appear
+	tracks := make(map[int]*midiTrack)
+	var order []int
+	trackFor := func(key int, name string) *midiTrack {
+		if t, ok := tracks[key]; ok {
+			return t
+		}
+		t := &midiTrack{name: name}
+		tracks[key] = t
+		order = append(order, key)
+		return t
+	}
+
+	channels := newChannelAllocator()
+	programs := make(map[byte]int) // Current program per MIDI channel, so changes are only sent when needed
+
+	active := make([]*activeNote, numChannels)
+	lastInstrument := make([]int, numChannels)
+	stop := func(ch, tick int) {
+		if n := active[ch]; n != nil {
+			n.track.add(tick, rankNoteOff, 0x80|n.channel, n.key, 0)
+			active[ch] = nil
+		}
+	}
+
+	for i, played := range rows {
+		tick := i * ticksPerRow
+		pattern := mod.GetCachedPattern(played.Pattern)
+		if played.Row >= len(pattern.Rows) {
+			continue
+		}
+		for ch, cell := range pattern.Rows[played.Row].Channels {
+			if ch >= numChannels {
+				break
+			}
+			if cell.Instrument > 0 {
+				lastInstrument[ch] = cell.Instrument
+			}
+
+			// A zero volume silences the channel; a note cut effect ends the note part way through the row
+			silenced := cellVolume(cell) == 0
+			cutAt := -1
+			if cut := cellCutTick(cell, played.Speed); cut >= 0 {
+				cutAt = tick + cut
+			}
+
+			switch {
+			case cell.Note == player.NoteOff || cell.Note == player.NoteCut || cell.Note == player.NoteFade:
+				stop(ch, tick)
+				continue
+			case cell.Note < player.NoteMin || cell.Note > player.NoteMax:
+				if silenced {
+					stop(ch, tick)
+				} else if cutAt >= 0 {
+					stop(ch, cutAt)
+				}
+				continue
+			}
+
+			stop(ch, tick)
+			if silenced || cutAt == tick {
+				continue // Never heard
+			}
+			instrument := lastInstrument[ch]
+			mapping := lookup(instrument)
+
+			var track *midiTrack
+			var key int
+			if opts.Tracks == TracksPerInstrument {
+				key = instrument
+				track = trackFor(key, instrumentTrackName(instrument, names))
+			} else {
+				key = ch
+				track = trackFor(key, channelTrackName(ch))
+			}
+
+			channel := byte(midiDrumChannel)
+			if !mapping.Drum {
+				program := clamp(mapping.Program, 0, 127)
+				channel = channels.melodic(program)
+				if programs[channel] != program+1 {
+					track.add(tick, rankSetup, 0xC0|channel, byte(program))
+					programs[channel] = program + 1
+				}
+			}
+
+			note := cell.Note - 1 + mapping.Transpose // libopenmpt C-0 is 1, MIDI C-0 is 0
+			if mapping.Drum && mapping.Key > 0 {
+				note = mapping.Key
+			}
+			n := &activeNote{track: track, channel: channel, key: byte(clamp(note, 0, 127))}
+			track.add(tick, rankNoteOn, 0x90|n.channel, n.key, byte(cellVelocity(cell)))
+			active[ch] = n
+			if cutAt > tick {
+				stop(ch, cutAt)
+			}
+		}
+	}
+
+	end := len(rows) * ticksPerRow
+	for ch := range active {
+		stop(ch, end)
+	}
+	if programs := channels.programs(); programs > midiMelodicChannels && opts.Warn != nil {
+		opts.Warn(fmt.Sprintf("%d GM programs for %d melodic MIDI channels; some share a channel and switch with program changes", programs, midiMelodicChannels))
+	}
+
+	sort.Ints(order)
+	all := []*midiTrack{conductor}
+	for _, key := range order {
+		all = append(all, tracks[key])
+	}
+	return writeSMF(w, all, end)
+}
+
+// rowSeconds picks the row length: speed/tempo in classic tempo mode, the measurement otherwise
+func rowSeconds(row player.PlayedRow, measured float64) float64 {
+	if row.Speed <= 0 || row.Tempo <= 0 {
+		return measured
+	}
+	nominal := float64(row.Speed) * 2.5 / float64(row.Tempo)
+	if measured > 0 && math.Abs(measured-nominal) > nominal*midiMeasuredTolerance {
+		return measured
+	}
+	return nominal
+}
+
+// cellVolume is the volume set by the volume column (or MOD/XM Cxx), 0-64, or -1 if none
+func cellVolume(cell player.PatternCell) int {
+	switch {
+	case cell.VolumeEffect == player.VolumeEffectVolume:
+		return cell.Volume
+	case cell.Effect == player.EffectVolume:
+		return cell.Parameter
+	}
+	return -1
+}
+
+// cellVelocity maps the cell's volume from 0-64 to MIDI velocity 1-127
+func cellVelocity(cell player.PatternCell) int {
+	volume := cellVolume(cell)
+	if volume < 0 {
+		return midiDefaultVelocity
+	}
+	return clamp(min(volume, 64)*127/64, 1, 127)
+}
+
+// cellCutTick is how many MIDI ticks into the row an ECx/SCx note cut lands, or -1 without one
+// Cuts on a tick past the row's speed never happen, as in the trackers
+func cellCutTick(cell player.PatternCell, speed int) int {
+	if cell.Effect != player.EffectModExtended && cell.Effect != player.EffectS3MExtended {
+		return -1
+	}
+	if cell.Parameter>>4 != 0xC {
+		return -1
+	}
+	if speed <= 0 {
+		speed = midiDefaultSpeed
+	}
+	at := cell.Parameter & 0x0F
+	if at >= speed {
+		return -1
+	}
+	return at * (midiPPQ / midiRowsPerBeat) / speed
+}
+
+// channelAllocator hands out melodic MIDI channels per GM program, so module channels playing
+// the same instrument share one. It skips the drum channel and wraps after midiMelodicChannels
+// programs, where programs have to share and switch with program changes.
+type channelAllocator struct {
+	channels map[int]byte
+}
+
+func newChannelAllocator() *channelAllocator {
+	return &channelAllocator{channels: make(map[int]byte)}
+}
+
+// melodic returns the MIDI channel for a GM program
+func (a *channelAllocator) melodic(program int) byte {
+	if c, ok := a.channels[program]; ok {
+		return c
+	}
+	c := byte(len(a.channels) % midiMelodicChannels)
+	if c >= midiDrumChannel {
+		c++
+	}
+	a.channels[program] = c
+	return c
+}
+
+// programs is the number of distinct programs seen so far
+func (a *channelAllocator) programs() int {
+	return len(a.channels)
+}
+
+func channelTrackName(ch int) string {
+	return fmt.Sprintf("Channel %d", ch+1)
+}
+
+func instrumentTrackName(instrument int, names []string) string {
+	if instrument >= 1 && instrument <= len(names) && names[instrument-1] != "" {
+		return fmt.Sprintf("%02X %s", instrument, names[instrument-1])
+	}
+	return fmt.Sprintf("Instrument %02X", instrument)
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// writeSMF writes the header and every track, each closed with end-of-track at end
+func writeSMF(w io.Writer, tracks []*midiTrack, end int) error {
+	bw := bufio.NewWriter(w)
+
+	header := []byte("MThd")
+	header = binary.BigEndian.AppendUint32(header, 6)
+	header = binary.BigEndian.AppendUint16(header, 1) // Type 1: simultaneous tracks
+	header = binary.BigEndian.AppendUint16(header, uint16(len(tracks)))
+	header = binary.BigEndian.AppendUint16(header, midiPPQ)
+	bw.Write(header)
+
+	for _, t := range tracks {
+		if t.name != "" {
+			// The name goes first, ahead of the tick-0 tempo and program changes
+			t.meta(0, 0x03, []byte(t.name))
+			t.events = append(t.events[len(t.events)-1:], t.events[:len(t.events)-1]...)
+		}
+		sort.SliceStable(t.events, func(i, j int) bool {
+			if t.events[i].tick != t.events[j].tick {
+				return t.events[i].tick < t.events[j].tick
+			}
+			return t.events[i].rank < t.events[j].rank
+		})
+
+		var body []byte
+		last := 0
+		for _, e := range t.events {
+			body = appendVLQ(body, e.tick-last)
+			body = append(body, e.data...)
+			last = e.tick
+		}
+		body = appendVLQ(body, max(0, end-last))
+		body = append(body, 0xFF, 0x2F, 0x00)
+
+		chunk := []byte("MTrk")
+		chunk = binary.BigEndian.AppendUint32(chunk, uint32(len(body)))
+		bw.Write(chunk)
+		bw.Write(body)
+	}
+
+	return bw.Flush()
+}
+
+// appendVLQ appends a MIDI variable-length quantity
+func appendVLQ(b []byte, v int) []byte {
+	var tmp [4]byte
+	i := len(tmp) - 1
+	tmp[i] = byte(v & 0x7F)
+	for v >>= 7; v > 0 && i > 0; v >>= 7 {
+		i--
+		tmp[i] = byte(v&0x7F) | 0x80
+	}
+	return append(b, tmp[i:]...)
+}
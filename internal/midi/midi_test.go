@@ -0,0 +1,140 @@
+package midi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+func TestAppendVLQ(t *testing.T) {
+	tests := []struct {
+		v    int
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{0x40, []byte{0x40}},
+		{0x7F, []byte{0x7F}},
+		{0x80, []byte{0x81, 0x00}},
+		{480, []byte{0x83, 0x60}},
+		{0x2000, []byte{0xC0, 0x00}},
+		{0x3FFF, []byte{0xFF, 0x7F}},
+		{0x4000, []byte{0x81, 0x80, 0x00}},
+		{0x1FFFFF, []byte{0xFF, 0xFF, 0x7F}},
+		{0x200000, []byte{0x81, 0x80, 0x80, 0x00}},
+		{0x0FFFFFFF, []byte{0xFF, 0xFF, 0xFF, 0x7F}},
+	}
+	for _, tt := range tests {
+		if got := appendVLQ([]byte{0xAA}, tt.v); !bytes.Equal(got, append([]byte{0xAA}, tt.want...)) {
+			t.Errorf("appendVLQ(%#x) = % X, want AA % X", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestWriteSMFEventOrder(t *testing.T) {
+	// Added out of order: on the same tick note-offs come first, then setup, then note-ons
+	track := &midiTrack{name: "T"}
+	track.add(0, rankNoteOn, 0x90, 60, 100)
+	track.add(0, rankSetup, 0xC0, 5)
+	track.add(480, rankNoteOn, 0x90, 62, 100)
+	track.add(480, rankNoteOff, 0x80, 60, 0)
+	track.add(960, rankNoteOff, 0x80, 62, 0)
+
+	var buf bytes.Buffer
+	if err := writeSMF(&buf, []*midiTrack{track}, 960); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		'M', 'T', 'h', 'd', 0, 0, 0, 6,
+		0, 1, // Type 1
+		0, 1, // One track
+		0x01, 0xE0, // 480 PPQ
+		'M', 'T', 'r', 'k', 0, 0, 0, 30,
+		0x00, 0xFF, 0x03, 0x01, 'T', // Name first
+		0x00, 0xC0, 0x05, // Program change before the note-on
+		0x00, 0x90, 60, 100,
+		0x83, 0x60, 0x80, 60, 0, // Note-off before the note-on on the same tick
+		0x00, 0x90, 62, 100,
+		0x83, 0x60, 0x80, 62, 0,
+		0x00, 0xFF, 0x2F, 0x00, // End of track
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("writeSMF wrote\n% X\nwant\n% X", buf.Bytes(), want)
+	}
+}
+
+func TestChannelAllocator(t *testing.T) {
+	a := newChannelAllocator()
+
+	// Programs get channels in order of first use, skipping the drum channel (9)
+	want := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 10, 11, 12, 13, 14, 15}
+	for program, channel := range want {
+		if got := a.melodic(program * 2); got != channel {
+			t.Errorf("program %d: channel %d, want %d", program*2, got, channel)
+		}
+	}
+
+	// The same program keeps its channel
+	if got := a.melodic(6); got != 3 {
+		t.Errorf("program 6 again: channel %d, want 3", got)
+	}
+
+	// Past 15 programs the channels wrap, still around the drum channel
+	for i, channel := range []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 10} {
+		if got := a.melodic(100 + i); got != channel {
+			t.Errorf("program %d: channel %d, want %d", 100+i, got, channel)
+		}
+	}
+	if got := a.programs(); got != 25 {
+		t.Errorf("programs() = %d, want 25", got)
+	}
+}
+
+func TestCellCutTick(t *testing.T) {
+	tests := []struct {
+		name  string
+		cell  player.PatternCell
+		speed int
+		want  int
+	}{
+		{"EC3 at speed 6", player.PatternCell{Effect: player.EffectModExtended, Parameter: 0xC3}, 6, 60},
+		{"EC0 cuts at once", player.PatternCell{Effect: player.EffectModExtended, Parameter: 0xC0}, 6, 0},
+		{"SC2 at speed 4", player.PatternCell{Effect: player.EffectS3MExtended, Parameter: 0xC2}, 4, 60},
+		{"EC6 is past the row", player.PatternCell{Effect: player.EffectModExtended, Parameter: 0xC6}, 6, -1},
+		{"unknown speed", player.PatternCell{Effect: player.EffectModExtended, Parameter: 0xC3}, 0, 60},
+		{"E93 is a retrigger", player.PatternCell{Effect: player.EffectModExtended, Parameter: 0x93}, 6, -1},
+		{"C03 is a volume", player.PatternCell{Effect: player.EffectVolume, Parameter: 0xC3}, 6, -1},
+		{"no effect", player.PatternCell{}, 6, -1},
+	}
+	for _, tt := range tests {
+		if got := cellCutTick(tt.cell, tt.speed); got != tt.want {
+			t.Errorf("%s: cellCutTick = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCellVolume(t *testing.T) {
+	tests := []struct {
+		name     string
+		cell     player.PatternCell
+		volume   int
+		velocity int
+	}{
+		{"no volume", player.PatternCell{}, -1, midiDefaultVelocity},
+		{"v64", player.PatternCell{VolumeEffect: player.VolumeEffectVolume, Volume: 64}, 64, 127},
+		{"v32", player.PatternCell{VolumeEffect: player.VolumeEffectVolume, Volume: 32}, 32, 63},
+		{"v00", player.PatternCell{VolumeEffect: player.VolumeEffectVolume, Volume: 0}, 0, 1},
+		{"C20", player.PatternCell{Effect: player.EffectVolume, Parameter: 0x20}, 0x20, 63},
+		{"C00", player.PatternCell{Effect: player.EffectVolume, Parameter: 0}, 0, 1},
+		{"C7F is clamped", player.PatternCell{Effect: player.EffectVolume, Parameter: 0x7F}, 0x7F, 127},
+	}
+	for _, tt := range tests {
+		if got := cellVolume(tt.cell); got != tt.volume {
+			t.Errorf("%s: cellVolume = %d, want %d", tt.name, got, tt.volume)
+		}
+		if got := cellVelocity(tt.cell); got != tt.velocity {
+			t.Errorf("%s: cellVelocity = %d, want %d", tt.name, got, tt.velocity)
+		}
+	}
+}
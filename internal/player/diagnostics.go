@@ -0,0 +1,73 @@
+package player
+
+/*
+#include <stdint.h>
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime/cgo"
+	"strings"
+	"sync"
+)
+
+// Load error kinds, matched with errors.Is against the error returned by LoadModule*
+var (
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	ErrCorrupt           = errors.New("corrupted or truncated file")
+	ErrOutOfMemory       = errors.New("out of memory")
+)
+
+// LoadError describes why libopenmpt refused a module
+// It unwraps to one of ErrUnsupportedFormat, ErrCorrupt or ErrOutOfMemory
+type LoadError struct {
+	Kind    error    // ErrUnsupportedFormat, ErrCorrupt or ErrOutOfMemory
+	Code    int      // libopenmpt error code (0 if none was reported)
+	Message string   // libopenmpt error message
+	Log     []string // Log lines emitted by libopenmpt while loading
+}
+
+func (e *LoadError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("failed to load module: %v", e.Kind)
+	}
+	return fmt.Sprintf("failed to load module: %v (libopenmpt: %s)", e.Kind, e.Message)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Kind
+}
+
+// moduleLog collects libopenmpt log lines for one module (load warnings and later notes)
+// It is passed to C as a cgo.Handle so the callback can find it again
+type moduleLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *moduleLog) add(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range strings.Split(message, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			l.lines = append(l.lines, line)
+		}
+	}
+}
+
+func (l *moduleLog) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+//export gomodLogFunc
+func gomodLogFunc(message *C.char, user C.uintptr_t) {
+	if message == nil || user == 0 {
+		return
+	}
+	if l, ok := cgo.Handle(user).Value().(*moduleLog); ok {
+		l.add(C.GoString(message))
+	}
+}
@@ -0,0 +1,203 @@
+package player
+
+import "time"
+
+// FadeOptions configures when playback fades out and ends on its own
+type FadeOptions struct {
+	// Loops is how many times the song plays through before it fades out.
+	// 0 ends it where libopenmpt detects the end or loop point, -1 loops forever.
+	Loops int
+	// MaxDuration fades out so that playback ends after this much audio (0 = no limit)
+	MaxDuration time.Duration
+	// Fade is the length of the fade-out for loops, the max duration and the sleep timer
+	Fade time.Duration
+}
+
+// StopReason is why a fade-out is running
+type StopReason int
+
+const (
+	StopNone StopReason = iota
+	StopSleep
+	StopAfterCurrent
+	StopLoops
+	StopMaxDuration
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case StopSleep:
+		return "sleep"
+	case StopAfterCurrent:
+		return "song end"
+	case StopLoops:
+		return "loops"
+	case StopMaxDuration:
+		return "max duration"
+	}
+	return ""
+}
+
+// StopStatus describes the pending automatic stops, for the header
+type StopStatus struct {
+	SleepAt      time.Time     // Sleep timer deadline (zero = off)
+	AfterCurrent bool          // Stop at the song's end or loop point
+	Loop         int           // Loop points passed so far
+	Loops        int           // FadeOptions.Loops
+	MaxLeft      time.Duration // Audio left before the max-duration fade has ended (0 = no limit)
+	Fading       StopReason    // Why a fade-out is running, StopNone if none is
+}
+
+// fadeStage is the fade-out stage of the render path, after the pause envelope
+// It counts the audio and loop points rendered and ends playback for the sleep timer,
+// stop-after-current, and the loop and duration limits. Guarded by the player's queueMu.
+type fadeStage struct {
+	opts         FadeOptions
+	env          envelope
+	reason       StopReason
+	played       int64 // Frames rendered from the module since playback (re)started, across seeks
+	loops        int
+	lastSeconds  float64 // Song position after the previous render, -1 after a seek
+	sleepAt      time.Time
+	afterCurrent bool
+}
+
+func newFadeStage() fadeStage {
+	return fadeStage{env: newEnvelope(), lastSeconds: -1}
+}
+
+// restart forgets the loops and audio counted so far and cancels a running fade
+func (f *fadeStage) restart() {
+	f.env = newEnvelope()
+	f.reason = StopNone
+	f.played, f.loops, f.lastSeconds = 0, 0, -1
+	f.afterCurrent = false
+}
+
+// start begins a fade-out; a fade that is already running keeps going
+func (f *fadeStage) start(reason StopReason, frames int) {
+	if f.reason != StopNone {
+		return
+	}
+	f.reason = reason
+	f.env.rampTo(0, frames)
+}
+
+// cancel fades back in if a fade for reason is running
+func (f *fadeStage) cancel(reason StopReason) {
+	if f.reason != reason || reason == StopNone {
+		return
+	}
+	f.reason = StopNone
+	f.env.rampTo(1, pauseFadeFrames)
+}
+
+// process applies the stage to frames just rendered, which left the module at seconds
+// Returns true once a fade-out has reached silence and playback should end.
+func (f *fadeStage) process(buf []int16, frames int, seconds float64, now time.Time) bool {
+	bufStart := f.played // The fade is applied from the start of buf
+	f.played += int64(frames)
+
+	// libopenmpt's song position only goes back when it repeats the song; a backward Bxx jump
+	// that is part of the song keeps counting up
+	if f.lastSeconds >= 0 && seconds < f.lastSeconds {
+		f.loops++
+		switch {
+		case f.afterCurrent:
+			f.start(StopAfterCurrent, pauseFadeFrames)
+		case f.opts.Loops > 0 && f.loops >= f.opts.Loops:
+			f.start(StopLoops, durationFrames(f.opts.Fade))
+		}
+	}
+	f.lastSeconds = seconds
+
+	if limit := durationFrames(f.opts.MaxDuration); limit > 0 {
+		fade := min(durationFrames(f.opts.Fade), limit)
+		if f.played >= int64(limit-fade) {
+			f.start(StopMaxDuration, int(max(0, int64(limit)-bufStart)))
+		}
+	}
+
+	if !f.sleepAt.IsZero() {
+		if left := f.sleepAt.Sub(now); left <= f.opts.Fade {
+			f.start(StopSleep, durationFrames(max(0, left)))
+		}
+	}
+
+	f.env.apply(buf)
+	return f.reason != StopNone && f.env.done()
+}
+
+// status reports the stage for the header
+func (f *fadeStage) status() StopStatus {
+	st := StopStatus{
+		SleepAt:      f.sleepAt,
+		AfterCurrent: f.afterCurrent,
+		Loop:         f.loops,
+		Loops:        f.opts.Loops,
+		Fading:       f.reason,
+	}
+	if limit := durationFrames(f.opts.MaxDuration); limit > 0 {
+		st.MaxLeft = time.Duration(max(0, int64(limit)-f.played)) * time.Second / sampleRate
+	}
+	return st
+}
+
+// durationFrames converts a duration to frames at the output rate
+func durationFrames(d time.Duration) int {
+	return int(d.Seconds() * sampleRate)
+}
+
+// SetFadeOptions configures the loop and duration limits and the fade-out length
+// Looping is left to libopenmpt, so call this before Play.
+func (p *Player) SetFadeOptions(opts FadeOptions) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.module != nil {
+		repeat := 0
+		if opts.Loops != 0 {
+			repeat = -1 // The fade stage counts the loops
+		}
+		_ = p.module.SetRepeatCount(repeat)
+	}
+
+	p.queueMu.Lock()
+	p.fade.opts = opts
+	p.queueMu.Unlock()
+}
+
+// SetSleepTimer fades playback out so that it ends at the given time (zero turns the timer off)
+// Changing the timer during its fade fades back in, unless the new time is just as close.
+func (p *Player) SetSleepTimer(at time.Time) {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+
+	p.fade.sleepAt = at
+	p.fade.cancel(StopSleep) // The next render starts it again if it's still due
+}
+
+// SleepTimer returns the sleep timer's deadline, zero when it is off
+func (p *Player) SleepTimer() time.Time {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	return p.fade.sleepAt
+}
+
+// SetStopAfterCurrent ends playback at the song's end or next loop point instead of looping
+func (p *Player) SetStopAfterCurrent(stop bool) {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+
+	p.fade.afterCurrent = stop
+	if !stop {
+		p.fade.cancel(StopAfterCurrent)
+	}
+}
+
+// StopStatus reports the sleep timer, loop count and any fade-out in progress
+func (p *Player) StopStatus() StopStatus {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	return p.fade.status()
+}
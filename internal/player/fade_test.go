@@ -0,0 +1,191 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+// fadeFrames feeds the stage one frame at a time at a rising song position, starting at seconds
+// It returns how many frames were processed until the stage asked to end playback (-1 if it never
+// did within limit frames) and the last frame's left sample.
+func fadeFrames(f *fadeStage, seconds float64, limit int, now time.Time) (int, int16) {
+	buf := make([]int16, channelCount)
+	for i := 1; i <= limit; i++ {
+		buf[0], buf[1] = 10000, 10000
+		if f.process(buf, 1, seconds+float64(i)/sampleRate, now) {
+			return i, buf[0]
+		}
+	}
+	return -1, buf[0]
+}
+
+// within reports whether got is want give or take a frame of rounding
+func within(got, want int) bool {
+	return got >= want-1 && got <= want+1
+}
+
+func TestFadeStageLoops(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		loops     int
+		positions []float64 // Song position after each render
+		wantLoops int
+		wantFade  bool
+	}{
+		{"rising position", 1, []float64{1, 2, 3, 4}, 0, false},
+		{"one loop", 2, []float64{1, 2, 3, 0.5, 1}, 1, false},
+		{"loop limit reached", 2, []float64{1, 2, 0.5, 1, 2, 0.5}, 2, true},
+		{"loop to a later point", 1, []float64{10, 20, 15}, 1, true},
+		{"equal position is no loop", 1, []float64{1, 1, 1}, 0, false},
+		{"loop forever", -1, []float64{1, 0.5, 1, 0.5, 1, 0.5}, 3, false},
+		{"no limit", 0, []float64{1, 0.5}, 1, false},
+	}
+	for _, tt := range tests {
+		f := newFadeStage()
+		f.opts = FadeOptions{Loops: tt.loops, Fade: time.Second}
+		for _, seconds := range tt.positions {
+			f.process(make([]int16, channelCount), 1, seconds, now)
+		}
+		st := f.status()
+		if st.Loop != tt.wantLoops {
+			t.Errorf("%s: %d loops counted, want %d", tt.name, st.Loop, tt.wantLoops)
+		}
+		if fading := st.Fading == StopLoops; fading != tt.wantFade {
+			t.Errorf("%s: fading %v, want %v", tt.name, fading, tt.wantFade)
+		}
+	}
+}
+
+func TestFadeStageSeekIsNoLoop(t *testing.T) {
+	f := newFadeStage()
+	f.opts = FadeOptions{Loops: 1, Fade: time.Second}
+	f.process(make([]int16, channelCount), 1, 60, time.Now())
+	f.lastSeconds = -1 // As restartClockLocked does
+	f.process(make([]int16, channelCount), 1, 5, time.Now())
+	if st := f.status(); st.Loop != 0 || st.Fading != StopNone {
+		t.Errorf("after a seek: %d loops, fading %v; want none", st.Loop, st.Fading)
+	}
+}
+
+func TestFadeStageLoopFade(t *testing.T) {
+	fade := 100 * time.Millisecond
+	f := newFadeStage()
+	f.opts = FadeOptions{Loops: 1, Fade: fade}
+	f.process(make([]int16, channelCount), 1, 30, time.Now())
+
+	// The song loops back to 0 s: the next frames fade out over the fade length, then playback ends
+	frames, last := fadeFrames(&f, 0, 2*durationFrames(fade), time.Now())
+	if !within(frames, durationFrames(fade)) {
+		t.Errorf("fade ended after %d frames, want %d", frames, durationFrames(fade))
+	}
+	if last != 0 {
+		t.Errorf("last sample %d, want silence", last)
+	}
+}
+
+func TestFadeStageNoFade(t *testing.T) {
+	f := newFadeStage()
+	f.opts = FadeOptions{Loops: 1, Fade: 0}
+	f.process(make([]int16, channelCount), 1, 30, time.Now())
+
+	buf := []int16{10000, 10000}
+	if !f.process(buf, 1, 0, time.Now()) {
+		t.Fatal("playback kept going after the loop with no fade")
+	}
+	if buf[0] != 0 {
+		t.Errorf("sample %d after the cut, want silence", buf[0])
+	}
+}
+
+func TestFadeStageMaxDuration(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxDuration time.Duration
+		fade        time.Duration
+	}{
+		{"fade within the limit", 200 * time.Millisecond, 50 * time.Millisecond},
+		{"fade longer than the limit", 50 * time.Millisecond, time.Second},
+		{"no fade", 50 * time.Millisecond, 0},
+	}
+	for _, tt := range tests {
+		f := newFadeStage()
+		f.opts = FadeOptions{Loops: -1, MaxDuration: tt.maxDuration, Fade: tt.fade}
+
+		limit := durationFrames(tt.maxDuration)
+		frames, last := fadeFrames(&f, 0, 2*limit, time.Now())
+		if !within(frames, limit) {
+			t.Errorf("%s: playback ended after %d frames, want %d", tt.name, frames, limit)
+		}
+		if last != 0 {
+			t.Errorf("%s: last sample %d, want silence", tt.name, last)
+		}
+		if st := f.status(); st.Fading != StopMaxDuration || st.MaxLeft != 0 {
+			t.Errorf("%s: status %+v, want a finished max-duration fade", tt.name, st)
+		}
+	}
+}
+
+func TestFadeStageSleep(t *testing.T) {
+	now := time.Now()
+	f := newFadeStage()
+	f.opts = FadeOptions{Loops: -1, Fade: time.Second}
+	f.sleepAt = now.Add(2 * time.Second)
+
+	// Further away than the fade length: nothing happens yet
+	buf := []int16{10000, 10000}
+	if f.process(buf, 1, 1, now) || buf[0] != 10000 {
+		t.Fatalf("sleep timer faded early: sample %d", buf[0])
+	}
+
+	// Half the fade length left: fade over what's left
+	left := 500 * time.Millisecond
+	frames, last := fadeFrames(&f, 1, durationFrames(time.Second), f.sleepAt.Add(-left))
+	if !within(frames, durationFrames(left)) || last != 0 {
+		t.Errorf("sleep fade ended after %d frames at sample %d, want %d frames and silence", frames, last, durationFrames(left))
+	}
+}
+
+func TestFadeStageRestart(t *testing.T) {
+	f := newFadeStage()
+	f.opts = FadeOptions{Loops: 1, Fade: 10 * time.Millisecond}
+	f.process(make([]int16, channelCount), 1, 30, time.Now())
+	if frames, _ := fadeFrames(&f, 0, sampleRate, time.Now()); frames < 0 {
+		t.Fatal("loop fade never finished")
+	}
+
+	// Replaying after the stop starts from full volume with nothing counted
+	f.restart()
+	if st := f.status(); st.Loop != 0 || st.Fading != StopNone {
+		t.Errorf("after restart: %d loops, fading %v; want none", st.Loop, st.Fading)
+	}
+	buf := []int16{10000, 10000}
+	if f.process(buf, 1, 0.5, time.Now()) || buf[0] != 10000 {
+		t.Errorf("after restart: sample %d, want 10000 and no stop", buf[0])
+	}
+
+	// The options survive, so the next loop fades again
+	f.process(make([]int16, channelCount), 1, 30, time.Now())
+	if frames, _ := fadeFrames(&f, 0, sampleRate, time.Now()); frames < 0 {
+		t.Error("no fade on the first loop after restart")
+	}
+}
+
+func TestSetFadeOptions(t *testing.T) {
+	tests := []FadeOptions{
+		{Loops: 2, MaxDuration: 10 * time.Minute, Fade: 20 * time.Second},
+		{Loops: -1},
+		{},
+	}
+	for _, opts := range tests {
+		p := &Player{fade: newFadeStage()}
+		p.SetFadeOptions(opts)
+		if p.fade.opts != opts {
+			t.Errorf("SetFadeOptions(%+v) stored %+v", opts, p.fade.opts)
+		}
+		st := p.StopStatus()
+		if st.Loops != opts.Loops || st.MaxLeft != opts.MaxDuration {
+			t.Errorf("SetFadeOptions(%+v): status %+v", opts, st)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package player
+
+/*
+#cgo pkg-config: libopenmpt
+#include <libopenmpt/libopenmpt.h>
+#include <stdlib.h>
+*/
+import "C"
+import "strings"
+
+// ModuleInfo is everything libopenmpt reports about a module, without playing it
+type ModuleInfo struct {
+	Metadata     map[string]string `json:"metadata"` // Every openmpt_module_get_metadata key
+	MetadataKeys []string          `json:"-"`        // Keys in libopenmpt's order, for display
+
+	Duration     float64       `json:"duration_seconds"`
+	Subsongs     []SubsongInfo `json:"subsongs"`
+	InitialSpeed int           `json:"initial_speed"`
+	InitialTempo int           `json:"initial_tempo"`
+
+	NumChannels    int `json:"num_channels"`
+	NumPatterns    int `json:"num_patterns"`
+	NumOrders      int `json:"num_orders"`
+	NumInstruments int `json:"num_instruments"`
+	NumSamples     int `json:"num_samples"`
+
+	ChannelNames    []string `json:"channel_names"`
+	PatternNames    []string `json:"pattern_names"`
+	OrderNames      []string `json:"order_names"`
+	InstrumentNames []string `json:"instrument_names"`
+	SampleNames     []string `json:"sample_names"`
+}
+
+// SubsongInfo describes one subsong
+type SubsongInfo struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Duration float64 `json:"duration_seconds"`
+}
+
+// GetInfo probes the module for all of its metadata
+// Selecting subsongs to measure them rewinds playback, so call this before playing
+func (m *Module) GetInfo() ModuleInfo {
+	if m == nil {
+		return ModuleInfo{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return ModuleInfo{}
+	}
+
+	info := ModuleInfo{
+		Metadata:       make(map[string]string),
+		Duration:       float64(C.openmpt_module_get_duration_seconds(m.mod)),
+		NumChannels:    int(C.openmpt_module_get_num_channels(m.mod)),
+		NumPatterns:    int(C.openmpt_module_get_num_patterns(m.mod)),
+		NumOrders:      int(C.openmpt_module_get_num_orders(m.mod)),
+		NumInstruments: int(C.openmpt_module_get_num_instruments(m.mod)),
+		NumSamples:     int(C.openmpt_module_get_num_samples(m.mod)),
+	}
+
+	// Metadata keys come back as a single semicolon-separated string
+	for _, key := range strings.Split(takeString(C.openmpt_module_get_metadata_keys(m.mod)), ";") {
+		if key == "" {
+			continue
+		}
+		info.MetadataKeys = append(info.MetadataKeys, key)
+		info.Metadata[key] = m.getMetadataString(key)
+	}
+
+	// Measure each subsong, then restore the original selection
+	selected := C.openmpt_module_get_selected_subsong(m.mod)
+	numSubsongs := int(C.openmpt_module_get_num_subsongs(m.mod))
+	for i := 0; i < numSubsongs; i++ {
+		C.openmpt_module_select_subsong(m.mod, C.int32_t(i))
+		info.Subsongs = append(info.Subsongs, SubsongInfo{
+			Index:    i,
+			Name:     takeString(C.openmpt_module_get_subsong_name(m.mod, C.int32_t(i))),
+			Duration: float64(C.openmpt_module_get_duration_seconds(m.mod)),
+		})
+	}
+	C.openmpt_module_select_subsong(m.mod, selected)
+
+	// Selecting rewinds to the start, so "current" speed/tempo are the initial ones
+	info.InitialSpeed = int(C.openmpt_module_get_current_speed(m.mod))
+	info.InitialTempo = int(C.openmpt_module_get_current_tempo(m.mod))
+
+	info.ChannelNames = m.getNamesLocked(info.NumChannels, func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_channel_name(m.mod, i)
+	})
+	info.PatternNames = m.getNamesLocked(info.NumPatterns, func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_pattern_name(m.mod, i)
+	})
+	info.OrderNames = m.getNamesLocked(info.NumOrders, func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_order_name(m.mod, i)
+	})
+	info.InstrumentNames = m.getNamesLocked(info.NumInstruments, func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_instrument_name(m.mod, i)
+	})
+	info.SampleNames = m.getNamesLocked(info.NumSamples, func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_sample_name(m.mod, i)
+	})
+
+	return info
+}
+
+// getNamesLocked fetches count raw names (0-based) with one of the get_*_name functions
+// Unlike GetInstrumentName, empty names stay empty so indices line up with the module
+func (m *Module) getNamesLocked(count int, get func(C.int32_t) *C.char) []string {
+	names := make([]string, count)
+	for i := range names {
+		names[i] = takeString(get(C.int32_t(i)))
+	}
+	return names
+}
+
+// takeString converts a libopenmpt-owned string and frees it
+func takeString(s *C.char) string {
+	if s == nil {
+		return ""
+	}
+	defer C.openmpt_free_string(s)
+	return C.GoString(s)
+}
@@ -162,6 +162,52 @@ func (m *Module) GetRowInstruments(row int) map[int]int {
 	return activeMap
 }
 
+// GetRawInstrumentNames returns every instrument name exactly as stored (0-based, blanks kept)
+// Artists often use the names as a text block for greetings, so nothing is trimmed or filled in
+func (m *Module) GetRawInstrumentNames() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return nil
+	}
+	return m.getNamesLocked(int(C.openmpt_module_get_num_instruments(m.mod)), func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_instrument_name(m.mod, i)
+	})
+}
+
+// GetRawSampleNames returns every sample name exactly as stored (0-based, blanks kept)
+func (m *Module) GetRawSampleNames() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return nil
+	}
+	return m.getNamesLocked(int(C.openmpt_module_get_num_samples(m.mod)), func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_sample_name(m.mod, i)
+	})
+}
+
+// GetChannelNames returns the channel names stored in the module (0-based, blank if unnamed)
+func (m *Module) GetChannelNames() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return nil
+	}
+	return m.getNamesLocked(int(C.openmpt_module_get_num_channels(m.mod)), func(i C.int32_t) *C.char {
+		return C.openmpt_module_get_channel_name(m.mod, i)
+	})
+}
+
 // Instrument represents a sample or instrument
 type Instrument struct {
 	ID   int
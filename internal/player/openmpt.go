@@ -9,10 +9,34 @@ package player
 
 // Force declaration if missing from pkg-config header path or visibility
 float openmpt_module_get_current_channel_vu_mono( openmpt_module * mod, int32_t channel );
+float openmpt_module_get_current_channel_vu_left( openmpt_module * mod, int32_t channel );
+float openmpt_module_get_current_channel_vu_right( openmpt_module * mod, int32_t channel );
 double openmpt_module_get_position_seconds( openmpt_module * mod );
 double openmpt_module_set_position_seconds( openmpt_module * mod, double seconds );
 int openmpt_module_set_render_param( openmpt_module * mod, int param, int32_t value );
 
+// Log/error plumbing for loading (gomodLogFunc is exported from diagnostics.go)
+extern void gomodLogFunc(char *message, uintptr_t user);
+
+static void gomod_log_func(const char *message, void *user) {
+    gomodLogFunc((char *)message, (uintptr_t)user);
+}
+
+// Log AND store errors so the caller receives the code and message
+static int gomod_error_func(int error, void *user) {
+    (void)error;
+    (void)user;
+    return OPENMPT_ERROR_FUNC_RESULT_DEFAULT;
+}
+
+openmpt_module_ext * gomod_create_ext(const void *data, size_t size, uintptr_t log, int *error, const char **error_message) {
+    return openmpt_module_ext_create_from_memory(data, size, gomod_log_func, (void *)log, gomod_error_func, NULL, error, error_message, NULL);
+}
+
+int gomod_probe(const void *data, size_t size) {
+    return openmpt_probe_file_header(OPENMPT_PROBE_FILE_HEADER_FLAGS_DEFAULT, data, size, size, NULL, NULL, gomod_error_func, NULL, NULL, NULL);
+}
+
 // Wrapper helpers to call interface function pointers safe from CGo
 
 int ext_set_channel_mute(openmpt_module_ext *mod_ext, int32_t channel, int mute) {
@@ -68,12 +92,43 @@ double ext_get_channel_volume(openmpt_module_ext *mod_ext, int32_t channel) {
     return 1.0;
 }
 
+// Set channel panning (-1.0 left to 1.0 right), from the "interactive2" interface (libopenmpt 0.6+)
+int ext_set_channel_panning(openmpt_module_ext *mod_ext, int32_t channel, double panning) {
+    if (!mod_ext) return 0;
+    openmpt_module_ext_interface_interactive2 interactive;
+    memset(&interactive, 0, sizeof(interactive));
+
+    if (openmpt_module_ext_get_interface(mod_ext, "interactive2", &interactive, sizeof(interactive)) != 0) {
+        if (interactive.set_channel_panning) {
+            return interactive.set_channel_panning(mod_ext, channel, panning);
+        }
+    }
+    return 0;
+}
+
+double ext_get_channel_panning(openmpt_module_ext *mod_ext, int32_t channel) {
+    if (!mod_ext) return 0.0;
+    openmpt_module_ext_interface_interactive2 interactive;
+    memset(&interactive, 0, sizeof(interactive));
+
+    if (openmpt_module_ext_get_interface(mod_ext, "interactive2", &interactive, sizeof(interactive)) != 0) {
+        if (interactive.get_channel_panning) {
+            return interactive.get_channel_panning(mod_ext, channel);
+        }
+    }
+    return 0.0;
+}
+
 */
 import "C"
 import (
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"runtime/cgo"
+	"strings"
 	"sync"
 	"unsafe"
 )
@@ -84,8 +139,11 @@ type Module struct {
 	mod            *C.openmpt_module
 	mu             sync.Mutex
 	patternCache   map[int]*CachedPattern
+	orderCache     []int
 	cachedMetadata *Metadata
-	channelMuted   []bool // Track which channels are muted
+	channelMuted   []bool     // Track which channels are muted
+	log            *moduleLog // libopenmpt log lines (load warnings etc.)
+	logHandle      cgo.Handle // Keeps log reachable from the C log callback
 }
 
 // LoadModule loads a tracker module from a file path
@@ -96,33 +154,82 @@ func LoadModule(path string) (*Module, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	return loadModuleFromMemory(filedata)
+}
+
+// LoadModuleFromReader loads a tracker module by reading r to EOF
+// Useful for stdin or modules streamed out of an asset store
+func LoadModuleFromReader(r io.Reader) (*Module, error) {
+	filedata, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module data: %w", err)
+	}
+
+	return loadModuleFromMemory(filedata)
+}
+
+// LoadModuleFS loads a tracker module named name from fsys (e.g. an embed.FS)
+func LoadModuleFS(fsys fs.FS, name string) (*Module, error) {
+	filedata, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return loadModuleFromMemory(filedata)
+}
+
+// loadModuleFromMemory creates a module from an in-memory copy of the file
+// libopenmpt copies what it needs, so filedata can be released afterwards
+func loadModuleFromMemory(filedata []byte) (*Module, error) {
+	if len(filedata) == 0 {
+		return nil, &LoadError{Kind: ErrCorrupt, Message: "empty file"}
+	}
+
+	// Collect libopenmpt's log output (warnings, loader notes)
+	// libopenmpt keeps the log callback for the module's lifetime, so the handle
+	// is only released on failure here, or in Close
+	logs := &moduleLog{}
+	handle := cgo.NewHandle(logs)
+
+	var errCode C.int
+	var errMessage *C.char
+
 	// Create module from memory using extended API
 	// Note: We use openmpt_module_ext_create_from_memory (not ...2) as it is the standard ext API
-	modExt := C.openmpt_module_ext_create_from_memory(
+	modExt := C.gomod_create_ext(
 		unsafe.Pointer(&filedata[0]),
 		C.size_t(len(filedata)),
-		nil, // log func
-		nil, // log user
-		nil, // error func
-		nil, // error user
-		nil, // error code
-		nil, // error message
-		nil, // initial ctls
+		C.uintptr_t(handle),
+		&errCode,
+		&errMessage,
 	)
 
 	if modExt == nil {
-		return nil, errors.New("failed to load module: unsupported format or corrupted file")
+		loadErr := &LoadError{
+			Code: int(errCode),
+			Log:  logs.snapshot(),
+		}
+		if errMessage != nil {
+			loadErr.Message = C.GoString(errMessage)
+			C.openmpt_free_string(errMessage)
+		}
+		loadErr.Kind = classifyLoadError(filedata, loadErr.Code)
+		handle.Delete()
+		return nil, loadErr
 	}
 
 	mod := C.openmpt_module_ext_get_module(modExt)
 	if mod == nil {
 		C.openmpt_module_ext_destroy(modExt)
+		handle.Delete()
 		return nil, errors.New("failed to get module interface from extended module")
 	}
 
 	m := &Module{
-		modExt: modExt,
-		mod:    mod,
+		modExt:    modExt,
+		mod:       mod,
+		log:       logs,
+		logHandle: handle,
 	}
 
 	// Initialize channel muted state
@@ -132,6 +239,54 @@ func LoadModule(path string) (*Module, error) {
 	return m, nil
 }
 
+// classifyLoadError maps a failed load onto one of the Err* kinds
+// libopenmpt reports most loader failures as a generic exception, so the header probe
+// tells "not a module at all" apart from "looks like a module but is broken"
+func classifyLoadError(filedata []byte, code int) error {
+	if code == C.OPENMPT_ERROR_OUT_OF_MEMORY {
+		return ErrOutOfMemory
+	}
+
+	switch C.gomod_probe(unsafe.Pointer(&filedata[0]), C.size_t(len(filedata))) {
+	case C.OPENMPT_PROBE_FILE_HEADER_RESULT_SUCCESS:
+		// Header is recognised but the body failed to load
+		return ErrCorrupt
+	case C.OPENMPT_PROBE_FILE_HEADER_RESULT_WANTMOREDATA:
+		// The whole file is shorter than its own header
+		return ErrCorrupt
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// Warnings returns the messages libopenmpt logged for this module
+// (e.g. unknown effects, truncated samples). Empty for clean files.
+func (m *Module) Warnings() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.log == nil {
+		return nil
+	}
+	if lines := m.log.snapshot(); len(lines) > 0 {
+		return lines
+	}
+	if m.mod == nil {
+		return nil
+	}
+
+	// Fall back to the loader's own summary
+	var lines []string
+	for _, line := range strings.Split(m.getMetadataString("warnings"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
 // SetStereoSeparation sets the stereo separation percentage (0-200)
 // 0 = mono, 100 = default, 200 = full separation
 func (m *Module) SetStereoSeparation(percent int) error {
@@ -156,6 +311,24 @@ func (m *Module) SetStereoSeparation(percent int) error {
 	return nil
 }
 
+// SetRepeatCount sets how often the song repeats: 0 plays it once, -1 loops forever
+// libopenmpt treats returning to an already played row as the end (or repeat) of the song.
+func (m *Module) SetRepeatCount(count int) error {
+	if m == nil {
+		return fmt.Errorf("module is nil")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return fmt.Errorf("module is closed")
+	}
+
+	if C.openmpt_module_set_repeat_count(m.mod, C.int32_t(count)) != 1 {
+		return fmt.Errorf("failed to set repeat count to %d", count)
+	}
+	return nil
+}
+
 // SetInterpolationFilter sets the interpolation quality
 // 0 = default, 1 = none, 2 = linear, 4 = cubic, 8 = windowed sinc (best quality)
 func (m *Module) SetInterpolationFilter(length int) error {
@@ -183,8 +356,14 @@ type Metadata struct {
 	Title    string
 	Artist   string
 	Type     string
+	Format   string // Short format name as libopenmpt reports it ("it", "xm", "mod", ...)
 	Duration float64
 	Channels int
+
+	// Message is the song message, or libopenmpt's instrument/sample name listing if there is none
+	Message string
+	// MessageRaw is the song message exactly as stored (empty if the module has none)
+	MessageRaw string
 }
 
 func (m *Module) GetMetadata() Metadata {
@@ -219,8 +398,12 @@ func (m *Module) GetMetadata() Metadata {
 		Title:    m.getMetadataString("title"),
 		Artist:   m.getMetadataString("artist"),
 		Type:     m.getMetadataString("type_long"),
+		Format:   m.getMetadataString("type"),
 		Duration: float64(C.openmpt_module_get_duration_seconds(m.mod)),
 		Channels: int(C.openmpt_module_get_num_channels(m.mod)),
+
+		Message:    m.getMetadataString("message"),
+		MessageRaw: m.getMetadataString("message_raw"),
 	}
 	m.cachedMetadata = &metadata
 
@@ -286,6 +469,19 @@ func (m *Module) GetCurrentRow() int {
 	return int(C.openmpt_module_get_current_row(m.mod))
 }
 
+// GetCurrentSpeed returns the ticks per row at the render position
+func (m *Module) GetCurrentSpeed() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return 0
+	}
+	return int(C.openmpt_module_get_current_speed(m.mod))
+}
+
 // GetCurrentPattern returns the current pattern being played
 func (m *Module) GetCurrentPattern() int {
 	if m == nil {
@@ -299,6 +495,51 @@ func (m *Module) GetCurrentPattern() int {
 	return int(C.openmpt_module_get_current_pattern(m.mod))
 }
 
+// GetCurrentOrder returns the current order list position being rendered
+func (m *Module) GetCurrentOrder() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return 0
+	}
+	return int(C.openmpt_module_get_current_order(m.mod))
+}
+
+// ChannelVUs holds the current VU reading of every channel (0.0 to 1.0)
+type ChannelVUs struct {
+	Mono  []float64
+	Left  []float64
+	Right []float64
+}
+
+// GetChannelVUs reads mono, left and right VUs for all channels under a single lock
+func (m *Module) GetChannelVUs() ChannelVUs {
+	if m == nil {
+		return ChannelVUs{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return ChannelVUs{}
+	}
+
+	numChannels := int(C.openmpt_module_get_num_channels(m.mod))
+	vus := ChannelVUs{
+		Mono:  make([]float64, numChannels),
+		Left:  make([]float64, numChannels),
+		Right: make([]float64, numChannels),
+	}
+	for ch := 0; ch < numChannels; ch++ {
+		vus.Mono[ch] = float64(C.openmpt_module_get_current_channel_vu_mono(m.mod, C.int32_t(ch)))
+		vus.Left[ch] = float64(C.openmpt_module_get_current_channel_vu_left(m.mod, C.int32_t(ch)))
+		vus.Right[ch] = float64(C.openmpt_module_get_current_channel_vu_right(m.mod, C.int32_t(ch)))
+	}
+	return vus
+}
+
 // GetNumChannels returns the number of channels
 func (m *Module) GetNumChannels() int {
 	if m == nil {
@@ -338,6 +579,60 @@ func (m *Module) GetPatternNumRows(pattern int) int {
 	return int(C.openmpt_module_get_pattern_num_rows(m.mod, C.int(pattern)))
 }
 
+// Order list markers as returned by GetOrderList
+const (
+	OrderSkip = 0xFFFE // "+++" skip marker, playback continues with the next order
+	OrderEnd  = 0xFFFF // "---" end-of-song marker
+)
+
+// GetOrderList returns the pattern index for every order position
+// Markers are reported as OrderSkip/OrderEnd; the list is cached after the first call
+func (m *Module) GetOrderList() []int {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return nil
+	}
+	if m.orderCache != nil {
+		return m.orderCache
+	}
+
+	numOrders := int(C.openmpt_module_get_num_orders(m.mod))
+	numPatterns := int(C.openmpt_module_get_num_patterns(m.mod))
+	orders := make([]int, numOrders)
+	for i := range orders {
+		pat := int(C.openmpt_module_get_order_pattern(m.mod, C.int32_t(i)))
+		switch {
+		case pat == OrderSkip:
+			orders[i] = OrderSkip
+		case pat < 0 || pat >= numPatterns:
+			// Anything that isn't a real pattern ends the song
+			orders[i] = OrderEnd
+		default:
+			orders[i] = pat
+		}
+	}
+	m.orderCache = orders
+	return orders
+}
+
+// SetPositionOrderRow seeks to the given order and row
+// Returns the new position in seconds
+func (m *Module) SetPositionOrderRow(order, row int) float64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return 0
+	}
+	return float64(C.openmpt_module_set_position_order_row(m.mod, C.int32_t(order), C.int32_t(row)))
+}
+
 // GetPatternRowChannelCommand gets pattern data for a specific row/channel
 func (m *Module) GetPatternRowChannelCommand(pattern, row, channel, command int) int {
 	if m == nil {
@@ -400,6 +695,11 @@ func (m *Module) Close() {
 		C.openmpt_module_destroy(m.mod)
 		m.mod = nil
 	}
+	// No more log callbacks can arrive once the module is gone
+	if m.logHandle != 0 {
+		m.logHandle.Delete()
+		m.logHandle = 0
+	}
 }
 
 // ToggleChannelMute toggles the mute state of a channel
@@ -480,6 +780,61 @@ func (m *Module) SoloChannel(channel int) {
 	}
 }
 
+// SetChannelVolume sets a channel's volume multiplier (0.0 to 1.0)
+func (m *Module) SetChannelVolume(channel int, volume float64) {
+	if m == nil || m.modExt == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil || channel < 0 || channel >= len(m.channelMuted) {
+		return
+	}
+	volume = max(0, min(1, volume))
+	C.ext_set_channel_volume(m.modExt, C.int32_t(channel), C.double(volume))
+}
+
+// GetChannelVolume returns a channel's volume multiplier (1.0 if unavailable)
+func (m *Module) GetChannelVolume(channel int) float64 {
+	if m == nil || m.modExt == nil {
+		return 1
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil || channel < 0 || channel >= len(m.channelMuted) {
+		return 1
+	}
+	return float64(C.ext_get_channel_volume(m.modExt, C.int32_t(channel)))
+}
+
+// SetChannelPanning sets a channel's panning (-1.0 left to 1.0 right)
+// Panning commands in the pattern data can override it later
+func (m *Module) SetChannelPanning(channel int, panning float64) {
+	if m == nil || m.modExt == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil || channel < 0 || channel >= len(m.channelMuted) {
+		return
+	}
+	panning = max(-1, min(1, panning))
+	C.ext_set_channel_panning(m.modExt, C.int32_t(channel), C.double(panning))
+}
+
+// GetChannelPanning returns a channel's current panning (-1.0 left to 1.0 right)
+func (m *Module) GetChannelPanning(channel int) float64 {
+	if m == nil || m.modExt == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil || channel < 0 || channel >= len(m.channelMuted) {
+		return 0
+	}
+	return float64(C.ext_get_channel_panning(m.modExt, C.int32_t(channel)))
+}
+
 // IsChannelMuted returns the mute state of a channel
 func (m *Module) IsChannelMuted(channel int) bool {
 	if m == nil || channel < 0 || channel >= len(m.channelMuted) {
@@ -507,13 +862,62 @@ type PatternRow struct {
 	Channels  []PatternCell
 }
 
+// PatternCell holds the six raw libopenmpt commands of a cell plus its native text
 type PatternCell struct {
-	Note       int
-	Instrument int
-	Volume     int
-	Effect     int
+	Note         int
+	Instrument   int
+	VolumeEffect int // Volume column command (0 = none)
+	Effect       int
+	Volume       int // Volume column parameter
+	Parameter    int // Effect parameter
+
+	// Text is the cell as the original tracker shows it (e.g. "C-5 01 v40 A0F" or "C-5 01 v40 D0F"),
+	// using the format's own effect letters. Highlight has one libopenmpt highlight class per byte of Text.
+	Text      string
+	Highlight string
 }
 
+// Column offsets within a full PatternCell.Text ("C-5 01 v40 A0F")
+const (
+	CellNoteAt       = 0
+	CellNoteLen      = 3
+	CellInstrumentAt = 4
+	CellVolumeAt     = 7
+	CellEffectAt     = 11
+	CellParameterAt  = 12
+)
+
+// Highlight classes used in PatternCell.Highlight
+const (
+	HighlightEmpty        = '.'
+	HighlightNote         = 'n'
+	HighlightSpecialNote  = 'm'
+	HighlightInstrument   = 'i'
+	HighlightVolumeEffect = 'u'
+	HighlightVolume       = 'v'
+	HighlightEffect       = 'e'
+	HighlightParameter    = 'f'
+)
+
+// Special values of PatternCell.Note (1-120 are C-0 to B-9)
+const (
+	NoteNone = 0
+	NoteMin  = 1
+	NoteMax  = 120
+	NoteFade = 253 // "~~~" note fade
+	NoteCut  = 254 // "^^^" note cut
+	NoteOff  = 255 // "===" key off
+)
+
+// PatternCell.VolumeEffect and PatternCell.Effect values used outside the pattern view
+// These are OpenMPT's internal command numbers, the same for every format
+const (
+	VolumeEffectVolume = 1  // "v40" set volume
+	EffectVolume       = 13 // MOD/XM Cxx, S3M/IT have no set-volume effect
+	EffectModExtended  = 19 // MOD/XM Exy (ECx is note cut)
+	EffectS3MExtended  = 20 // S3M/IT Sxy (SCx is note cut)
+)
+
 // CachedPattern stores the full content of a pattern in Go memory
 type CachedPattern struct {
 	Rows []PatternRow
@@ -570,45 +974,84 @@ func (m *Module) GetPatternView(pattern, row, numChannels, visibleRows int, chan
 	return m.getPatternViewLocked(pattern, row, numChannels, visibleRows, snapshot)
 }
 
-// getPatternViewLocked is the internal helper that assumes the lock is held
-func (m *Module) getPatternViewLocked(currentPattern, currentRow, numChannels, visibleRows int, snapshot PatternSnapshot) PatternSnapshot {
-	// Mutex is expected to be held by caller (GetPatternView)
-	if m.mod == nil { // Added nil check
-		return PatternSnapshot{}
+// GetCachedPattern returns the full content of a pattern, loading it into the cache if needed
+// The returned rows are shared with the cache and must not be modified
+func (m *Module) GetCachedPattern(pattern int) *CachedPattern {
+	if m == nil {
+		return &CachedPattern{}
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return &CachedPattern{}
+	}
+	return m.cachedPatternLocked(pattern, int(C.openmpt_module_get_num_channels(m.mod)))
+}
+
+// PrefetchPatterns loads every pattern into the cache so whole-song scans don't stall on cgo calls
+// The lock is taken per pattern so playback keeps rendering while this runs
+func (m *Module) PrefetchPatterns() {
+	for pattern := 0; pattern < m.GetNumPatterns(); pattern++ {
+		m.GetCachedPattern(pattern)
+	}
+}
+
+// cachedPatternLocked returns the cached pattern, fetching the ENTIRE pattern on a miss
+// Mutex is expected to be held by caller
+func (m *Module) cachedPatternLocked(pattern, numChannels int) *CachedPattern {
 	// Ensure cache is initialized
 	if m.patternCache == nil {
 		m.patternCache = make(map[int]*CachedPattern)
 	}
 
 	// Check if this pattern is cached
-	cached, exists := m.patternCache[currentPattern]
-	if !exists {
-		// Cache Miss: Load the ENTIRE pattern now
-		// First get num rows for this pattern
-		numRows := int(C.openmpt_module_get_pattern_num_rows(m.mod, C.int(currentPattern)))
+	cached, exists := m.patternCache[pattern]
+	if exists {
+		return cached
+	}
 
-		rows := make([]PatternRow, numRows)
+	// Cache Miss: Load the ENTIRE pattern now
+	// First get num rows for this pattern
+	numRows := int(C.openmpt_module_get_pattern_num_rows(m.mod, C.int(pattern)))
 
-		for r := 0; r < numRows; r++ {
-			rowStr := PatternRow{
-				RowNumber: r,
-				Channels:  make([]PatternCell, numChannels),
+	rows := make([]PatternRow, numRows)
+
+	for r := 0; r < numRows; r++ {
+		rowStr := PatternRow{
+			RowNumber: r,
+			Channels:  make([]PatternCell, numChannels),
+		}
+		for c := 0; c < numChannels; c++ {
+			command := func(cmd C.int) int {
+				return int(C.openmpt_module_get_pattern_row_channel_command(m.mod, C.int(pattern), C.int(r), C.int(c), cmd))
 			}
-			for c := 0; c < numChannels; c++ {
-				rowStr.Channels[c] = PatternCell{
-					Note:       int(C.openmpt_module_get_pattern_row_channel_command(m.mod, C.int(currentPattern), C.int(r), C.int(c), 0)), // Note
-					Instrument: int(C.openmpt_module_get_pattern_row_channel_command(m.mod, C.int(currentPattern), C.int(r), C.int(c), 1)), // Inst
-					Volume:     int(C.openmpt_module_get_pattern_row_channel_command(m.mod, C.int(currentPattern), C.int(r), C.int(c), 2)), // Vol
-					Effect:     int(C.openmpt_module_get_pattern_row_channel_command(m.mod, C.int(currentPattern), C.int(r), C.int(c), 3)), // Effect
-				}
+			rowStr.Channels[c] = PatternCell{
+				Note:         command(C.OPENMPT_MODULE_COMMAND_NOTE),
+				Instrument:   command(C.OPENMPT_MODULE_COMMAND_INSTRUMENT),
+				VolumeEffect: command(C.OPENMPT_MODULE_COMMAND_VOLUMEEFFECT),
+				Effect:       command(C.OPENMPT_MODULE_COMMAND_EFFECT),
+				Volume:       command(C.OPENMPT_MODULE_COMMAND_VOLUME),
+				Parameter:    command(C.OPENMPT_MODULE_COMMAND_PARAMETER),
+				// Full width (0) gives every column: note, instrument, volume and effect
+				Text:      takeString(C.openmpt_module_format_pattern_row_channel(m.mod, C.int32_t(pattern), C.int32_t(r), C.int32_t(c), 0, 1)),
+				Highlight: takeString(C.openmpt_module_highlight_pattern_row_channel(m.mod, C.int32_t(pattern), C.int32_t(r), C.int32_t(c), 0, 1)),
 			}
-			rows[r] = rowStr
 		}
+		rows[r] = rowStr
+	}
+
+	cached = &CachedPattern{Rows: rows}
+	m.patternCache[pattern] = cached
+	return cached
+}
 
-		cached = &CachedPattern{Rows: rows}
-		m.patternCache[currentPattern] = cached
+// getPatternViewLocked is the internal helper that assumes the lock is held
+func (m *Module) getPatternViewLocked(currentPattern, currentRow, numChannels, visibleRows int, snapshot PatternSnapshot) PatternSnapshot {
+	// Mutex is expected to be held by caller (GetPatternView)
+	if m.mod == nil { // Added nil check
+		return PatternSnapshot{}
 	}
+	cached := m.cachedPatternLocked(currentPattern, numChannels)
 
 	// Calculate range for "Typewriter" style scrolling
 	half := visibleRows / 2
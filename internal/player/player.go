@@ -3,6 +3,7 @@ package player
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hajimehoshi/oto/v2"
@@ -44,13 +45,27 @@ type Player struct {
 	otoContext *oto.Context
 	otoPlayer  oto.Player
 	mu         sync.RWMutex
-	playing    bool
+	state      atomic.Int32 // TransportState; the audio reader advances it without taking mu
 
 	// Sync mechanism
 	stateQueue     []SyncState
 	queueMu        sync.Mutex
 	startSample    int64
 	samplesWritten int64
+
+	// Rendered PCM, keyed by the same sample clock as stateQueue
+	tap *OutputTap
+
+	// Held position (guarded by queueMu): shown while paused, and while a seek or resume has no
+	// audio yet. Row/tick steps move it. Once a pause has faded out the reader plays stepBuf, then
+	// silence, instead of the module, so the device never stops and resuming can't click.
+	held      SyncState
+	heldTime  float64
+	pauseFade bool // Pausing: the reader still renders the module until env reaches 0
+	stepping  bool
+	stepBuf   []int16
+	env       envelope
+	fade      fadeStage // Sleep timer, stop-after-current and loop/duration limits
 }
 
 // SyncState represents the state of the engine at a specific sample time
@@ -58,7 +73,11 @@ type SyncState struct {
 	SampleCount    int64
 	Row            int
 	Pattern        int
-	ChannelVolumes []float64
+	Order          int
+	ChannelVolumes []float64 // Mono VU per channel
+	ChannelLeft    []float64 // Left VU per channel
+	ChannelRight   []float64 // Right VU per channel
+	Tick           int       // Tick within Row reached by tick steps (paused only)
 }
 
 // NewPlayer creates a new player for the given module using an existing audio context
@@ -66,8 +85,10 @@ func NewPlayer(otoContext *oto.Context, module *Module) (*Player, error) {
 	p := &Player{
 		module:     module,
 		otoContext: otoContext,
-		playing:    false,
 		stateQueue: make([]SyncState, 0, 100),
+		tap:        newOutputTap(tapCapacity),
+		env:        newEnvelope(),
+		fade:       newFadeStage(),
 	}
 
 	return p, nil
@@ -76,24 +97,50 @@ func NewPlayer(otoContext *oto.Context, module *Module) (*Player, error) {
 // GetSyncedState returns the module state corresponding to the CURRENT playback time
 // precise to the audio buffer latency using hardware feedback
 func (p *Player) GetSyncedState() (int, int, []float64) {
+	state := p.GetSyncedPosition()
+	return state.Pattern, state.Row, state.ChannelVolumes
+}
+
+// GetSyncedPosition returns the full SyncState (including order) the hardware is playing
+// While paused, and until audio from a seek or resume arrives, it returns the held position
+func (p *Player) GetSyncedPosition() SyncState {
 	p.mu.RLock()
-	if p.otoPlayer == nil || !p.playing {
+	if p.otoPlayer == nil {
 		p.mu.RUnlock()
-		return 0, 0, nil
+		return SyncState{}
 	}
 	p.mu.RUnlock() // Release generic lock before acquiring queue lock
 
 	p.queueMu.Lock()
 	defer p.queueMu.Unlock()
 
+	if p.holdingLocked() {
+		return p.held
+	}
+	return p.livePositionLocked()
+}
+
+// holdingLocked reports whether the held position stands in for the hardware's (queueMu held)
+// A pause that is still fading out keeps following the audio that is fading.
+func (p *Player) holdingLocked() bool {
+	st := p.State()
+	return (st == TransportPaused && !p.pauseFade) || st == TransportEnded || len(p.stateQueue) == 0
+}
+
+// restartClockLocked restarts the sync clock after the module was moved to seconds (queueMu held)
+func (p *Player) restartClockLocked(seconds float64) {
+	p.stateQueue = p.stateQueue[:0]
+	// samplesWritten is in frames (samples per channel), so GetSyncedTime follows the new position
+	p.samplesWritten = int64(seconds * float64(sampleRate))
+	p.fade.lastSeconds = -1 // A seek is not a loop
+}
+
+// livePositionLocked finds the queued state the hardware is playing (queueMu held, queue not empty)
+func (p *Player) livePositionLocked() SyncState {
 	// Calculate what sample the hardware is currently playing
 	// samplesWritten = total samples sent to oto
 	// UnplayedBufferSize = bytes buffered in driver (convert to samples)
 	// currentSample = samplesWritten - samplesBuffered
-	if len(p.stateQueue) == 0 {
-		return 0, 0, nil
-	}
-
 	unplayedBytes := p.otoPlayer.UnplayedBufferSize()
 	unplayedSamples := int64(unplayedBytes) / 4 // 4 bytes per stereo sample (16bit * 2)
 	currentSample := p.samplesWritten - unplayedSamples
@@ -130,13 +177,14 @@ func (p *Player) GetSyncedState() (int, int, []float64) {
 		p.stateQueue = newQueue
 	}
 
-	return bestState.Pattern, bestState.Row, bestState.ChannelVolumes
+	return bestState
 }
 
 // GetSyncedTime returns the current playback time in seconds, sync'd to hardware
+// While paused, and until audio from a seek or resume arrives, it returns the held position's time
 func (p *Player) GetSyncedTime() float64 {
 	p.mu.RLock()
-	if p.otoPlayer == nil || !p.playing {
+	if p.otoPlayer == nil {
 		p.mu.RUnlock()
 		return 0
 	}
@@ -145,6 +193,14 @@ func (p *Player) GetSyncedTime() float64 {
 	p.queueMu.Lock()
 	defer p.queueMu.Unlock()
 
+	if p.holdingLocked() {
+		return p.heldTime
+	}
+	return p.liveTimeLocked()
+}
+
+// liveTimeLocked is the time of the sample the hardware is playing (queueMu held)
+func (p *Player) liveTimeLocked() float64 {
 	unplayedBytes := p.otoPlayer.UnplayedBufferSize()
 	unplayedSamples := int64(unplayedBytes) / 4
 	currentSample := p.samplesWritten - unplayedSamples
@@ -156,12 +212,36 @@ func (p *Player) GetSyncedTime() float64 {
 	return float64(currentSample) / float64(sampleRate)
 }
 
+// GetSyncedSamples fills left and right with the output audio that ends at the sample
+// the hardware is playing right now, aligned the same way as GetSyncedState
+// Returns false (and leaves the buffers untouched) when paused, unless a step is sounding
+func (p *Player) GetSyncedSamples(left, right []float32) bool {
+	p.mu.RLock()
+	if p.otoPlayer == nil {
+		p.mu.RUnlock()
+		return false
+	}
+	p.mu.RUnlock()
+
+	p.queueMu.Lock()
+	if p.State() == TransportPaused && !p.pauseFade && !p.stepping {
+		p.queueMu.Unlock()
+		return false
+	}
+	unplayedSamples := int64(p.otoPlayer.UnplayedBufferSize()) / 4
+	currentSample := p.samplesWritten - unplayedSamples
+	p.queueMu.Unlock()
+
+	p.tap.Window(currentSample, left, right)
+	return true
+}
+
 // Play starts playback
 func (p *Player) Play(ctx context.Context) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if p.playing {
+	if p.State() != TransportStopped {
 		return nil
 	}
 
@@ -171,25 +251,36 @@ func (p *Player) Play(ctx context.Context) error {
 		player: p,
 	})
 
-	p.otoPlayer.Play()
-	p.playing = true
 	// Reset sync
 	p.queueMu.Lock()
-	p.stateQueue = p.stateQueue[:0]
-	p.samplesWritten = 0
+	p.restartClockLocked(0)
+	p.fade.restart()
 	p.queueMu.Unlock()
+	p.tap.reset()
+
+	p.state.Store(int32(TransportLoading))
+	p.otoPlayer.Play()
 
 	return nil
 }
 
-// IsPlaying returns whether audio is currently playing
+// IsPlaying reports whether audio is playing or about to (loading, seeking)
 func (p *Player) IsPlaying() bool {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	return p.playing
+	switch p.State() {
+	case TransportLoading, TransportPlaying, TransportSeeking:
+		return true
+	}
+	return false
 }
 
-// TogglePause toggles playback state
+// State returns the transport state
+func (p *Player) State() TransportState {
+	return TransportState(p.state.Load())
+}
+
+// TogglePause toggles playback state, fading out and back in
+// Pausing holds the position that was heard; resuming after row steps continues from the row
+// stepped into, and resuming after the end plays the song again from the top
 func (p *Player) TogglePause() bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -198,13 +289,67 @@ func (p *Player) TogglePause() bool {
 		return false
 	}
 
-	if p.playing {
-		p.otoPlayer.Pause()
-	} else {
+	switch p.State() {
+	case TransportStopped:
+		return false
+
+	case TransportPaused:
+		p.resumeLocked()
+
+	case TransportEnded:
+		p.otoPlayer.Reset()
+		seconds := p.module.SetPositionSeconds(0)
+		p.queueMu.Lock()
+		p.restartClockLocked(seconds)
+		p.held, p.heldTime = SyncState{}, seconds
+		p.env = newEnvelope()
+		p.fade.restart()
+		p.queueMu.Unlock()
+		p.state.Store(int32(TransportSeeking))
 		p.otoPlayer.Play()
+
+	default:
+		// The reader keeps rendering until the fade-out ends, then holds that position
+		p.queueMu.Lock()
+		if len(p.stateQueue) > 0 {
+			p.held = p.livePositionLocked()
+			p.heldTime = p.liveTimeLocked()
+		}
+		p.pauseFade = true
+		p.env.rampTo(0, pauseFadeFrames)
+		p.queueMu.Unlock()
+		p.state.Store(int32(TransportPaused))
 	}
-	p.playing = !p.playing
-	return p.playing
+	return p.IsPlaying()
+}
+
+// resumeLocked restarts rendering from the held position with a fade-in (p.mu held)
+func (p *Player) resumeLocked() {
+	// Drop the silence (or step audio) queued while paused
+	p.otoPlayer.Reset()
+
+	p.queueMu.Lock()
+	stepping := p.stepping
+	order, row := p.held.Order, p.held.Row
+	p.stepping, p.stepBuf, p.pauseFade = false, nil, false
+	p.queueMu.Unlock()
+
+	// After a fade-out the module stopped where the audio did; steps moved it, so seek back
+	var seconds float64
+	if stepping {
+		seconds = p.module.SetPositionOrderRow(order, row)
+	} else {
+		seconds = p.module.GetPositionSeconds()
+	}
+
+	p.queueMu.Lock()
+	p.restartClockLocked(seconds)
+	p.heldTime = seconds
+	p.env.rampTo(1, pauseFadeFrames)
+	p.queueMu.Unlock()
+
+	p.state.Store(int32(TransportPlaying))
+	p.otoPlayer.Play()
 }
 
 // Close cleans up resources
@@ -239,48 +384,71 @@ func (r *audioReader) Read(p []byte) (int, error) {
 	default:
 	}
 
+	// Paused (once faded out) or ended: play row steps, then silence, not the module
+	if n, ok := r.readHeld(p); ok {
+		return n, nil
+	}
+
 	// BEFORE rendering, capture the state that corresponds to the START of this buffer
 	row := r.module.GetCurrentRow()
 	pat := r.module.GetCurrentPattern()
+	order := r.module.GetCurrentOrder()
 
-	// Get VUs efficiently (module already has a helper or we can add one to Module if needed,
-	// but GetPatternSnapshot does it. Let's assume we can get them.)
-	// Actually we need to add GetChannelVolumes to Module to do this efficiently without building full snapshot.
-	// For now let's reuse GetPatternSnapshot logic or adding a small helper in Module.
-	// WAIT: We can use the C function directly if we were in the same package (which we are).
-	// But Module struct hides C types.
-	// Better approach: Module.GetSyncState()
-
-	// Temporarily: We will use a small helper we add to Module in next step, or just use GetPatternSnapshot(0) which is cheap?
-	// GetPatternSnapshot(0) builds a snapshot.
-	// Let's assume we added GetCurrentVolumes to module.
-	// Actually, let's just grab them via GetPatternSnapshot(0) for now, it's safe.
-	snap := r.module.GetPatternSnapshot(0)
+	// Per-channel VUs (mono plus left/right) at the start of this buffer
+	vus := r.module.GetChannelVUs()
 
 	// Push state to queue
 	r.player.queueMu.Lock()
+	startSample := r.player.samplesWritten
 	r.player.stateQueue = append(r.player.stateQueue, SyncState{
 		SampleCount:    r.player.samplesWritten,
 		Row:            row,
 		Pattern:        pat,
-		ChannelVolumes: snap.ChannelVolumes,
+		Order:          order,
+		ChannelVolumes: vus.Mono,
+		ChannelLeft:    vus.Left,
+		ChannelRight:   vus.Right,
 	})
 	r.player.queueMu.Unlock()
 
 	// Render audio from openmpt
 	frames := r.module.Read(r.buf[:])
 	if frames == 0 {
-		return 0, nil // End of module
+		// End of module: hold the last row and play silence from now on
+		r.player.queueMu.Lock()
+		r.player.endLocked(SyncState{Order: order, Row: row, Pattern: pat})
+		r.player.queueMu.Unlock()
+		return 0, nil
 	}
 
+	// Audio from the new position is on its way
+	r.player.state.CompareAndSwap(int32(TransportLoading), int32(TransportPlaying))
+	r.player.state.CompareAndSwap(int32(TransportSeeking), int32(TransportPlaying))
+
 	// Convert int16 samples to bytes
 	samples := frames * 2 // stereo
 	bytesWritten := samples * 2
 
+	// Where this buffer left the module, for the fade stage's loop count
+	endSeconds := r.module.GetPositionSeconds()
+
 	r.player.queueMu.Lock()
 	r.player.samplesWritten += int64(frames)
+	r.player.env.apply(r.buf[:samples])
+	if r.player.pauseFade && r.player.env.done() {
+		// Faded out: the audio stops here, so this is the position to hold
+		r.player.pauseFade = false
+		r.player.held = SyncState{Order: order, Row: row, Pattern: pat}
+		r.player.heldTime = float64(r.player.samplesWritten) / float64(sampleRate)
+	}
+	if r.player.fade.process(r.buf[:samples], frames, endSeconds, time.Now()) {
+		r.player.endLocked(SyncState{Order: order, Row: row, Pattern: pat})
+	}
 	r.player.queueMu.Unlock()
 
+	// Keep a copy for the visualizers, keyed by where this buffer starts
+	r.player.tap.write(startSample, r.buf[:samples])
+
 	for i := 0; i < samples; i++ {
 		p[i*2] = byte(r.buf[i] & 0xff)
 		p[i*2+1] = byte((r.buf[i] >> 8) & 0xff)
@@ -289,6 +457,24 @@ func (r *audioReader) Read(p []byte) (int, error) {
 	return bytesWritten, nil
 }
 
+// endLocked ends playback at the end of the module or of a fade-out, holding state (queueMu held)
+// The reader plays silence from then on; TogglePause plays the song again from the top.
+func (p *Player) endLocked(state SyncState) {
+	p.held = state
+	p.heldTime = float64(p.samplesWritten) / float64(sampleRate)
+	p.pauseFade = false
+	if p.fade.reason == StopSleep {
+		p.fade.sleepAt = time.Time{}
+	}
+	// The reader plays silence now, so full gain is ready for whatever plays next
+	p.fade.env = newEnvelope()
+	p.fade.reason = StopNone
+	p.fade.afterCurrent = false
+	if p.State() != TransportPaused {
+		p.state.Store(int32(TransportEnded))
+	}
+}
+
 // instantAction performs a common logic for instant mute/solo changes
 // It performs a flush & seek to make the change audible immediately (overcoming buffer latency)
 func (p *Player) instantAction(action func()) {
@@ -299,6 +485,12 @@ func (p *Player) instantAction(action func()) {
 		return
 	}
 
+	// Paused or ended: nothing is being rendered, so the change is heard from the next step or resume
+	if st := p.State(); st == TransportPaused || st == TransportEnded {
+		action()
+		return
+	}
+
 	// 1. Get current positions
 	renderPos := p.module.GetPositionSeconds()
 
@@ -322,18 +514,63 @@ func (p *Player) instantAction(action func()) {
 	// Reset clears the underlying buffer and pauses
 	p.otoPlayer.Reset()
 
-	// 6. Reset sync state to match the seek
+	// 6. Reset sync state to match the seek, holding the heard position until new audio arrives
 	p.queueMu.Lock()
-	p.stateQueue = p.stateQueue[:0]
-	// Reset samplesWritten so GetSyncedTime() remains accurate to the new position
-	// seekTarget is in seconds, samplesWritten is in frames (samples per channel)
-	p.samplesWritten = int64(seekTarget * float64(sampleRate))
+	if len(p.stateQueue) > 0 {
+		p.held = p.livePositionLocked()
+	}
+	p.heldTime = seekTarget
+	p.restartClockLocked(seekTarget)
 	p.queueMu.Unlock()
+	p.state.Store(int32(TransportSeeking))
 
-	// 7. Resume if we were playing
-	if p.playing {
-		p.otoPlayer.Play()
+	// 7. Resume
+	p.otoPlayer.Play()
+}
+
+// SeekOrderRow jumps playback to the given order and row
+// Like instantAction it flushes the device buffer so the jump is heard immediately.
+// While paused it only moves the held position, which row steps and resume start from.
+func (p *Player) SeekOrderRow(order, row int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.module == nil {
+		return
+	}
+
+	seconds := p.module.SetPositionOrderRow(order, row)
+	pattern := p.module.GetCurrentPattern()
+
+	if p.otoPlayer == nil {
+		return
+	}
+
+	// Flush Oto buffer (Reset clears the underlying buffer and pauses)
+	p.otoPlayer.Reset()
+
+	// Reset sync state to match the seek
+	paused := p.State() == TransportPaused
+	p.queueMu.Lock()
+	if p.State() == TransportEnded {
+		// Playing on after the song ended (or faded out) starts the loop and duration counts afresh
+		p.fade.restart()
 	}
+	p.restartClockLocked(seconds)
+	p.held = SyncState{Order: order, Row: row, Pattern: pattern}
+	p.heldTime = seconds
+	p.stepBuf = nil
+	if paused {
+		// A fade-out still in progress would otherwise resume rendering from the new position
+		p.pauseFade = false
+		p.env.rampTo(0, 0)
+	}
+	p.queueMu.Unlock()
+	if !paused {
+		p.state.Store(int32(TransportSeeking))
+	}
+
+	p.otoPlayer.Play()
 }
 
 // InstantMute toggles mute on a channel and performs a Flush & Seek to make it audible immediately
@@ -343,6 +580,20 @@ func (p *Player) InstantMute(channel int) {
 	})
 }
 
+// InstantChannelVolume changes a channel's volume by delta with Flush & Seek
+func (p *Player) InstantChannelVolume(channel int, delta float64) {
+	p.instantAction(func() {
+		p.module.SetChannelVolume(channel, p.module.GetChannelVolume(channel)+delta)
+	})
+}
+
+// InstantChannelPanning moves a channel's panning by delta with Flush & Seek
+func (p *Player) InstantChannelPanning(channel int, delta float64) {
+	p.instantAction(func() {
+		p.module.SetChannelPanning(channel, p.module.GetChannelPanning(channel)+delta)
+	})
+}
+
 // InstantSolo solos a channel (unmutes it, mutes others) with Flush & Seek
 func (p *Player) InstantSolo(channel int) {
 	p.instantAction(func() {
@@ -0,0 +1,165 @@
+package player
+
+import "sort"
+
+// SongStats summarises how a song uses its pattern data
+// Usage counts follow the order list (every subsong), so a pattern listed twice counts twice
+type SongStats struct {
+	NumPatterns        int   `json:"num_patterns"`
+	ReferencedPatterns []int `json:"referenced_patterns"` // Patterns in the order list, ascending
+	UnusedPatterns     []int `json:"unused_patterns"`     // Patterns never reached by the order list
+
+	OrderRows   int     `json:"order_rows"` // Rows of every pattern in the order list; jumps and breaks aren't followed
+	TotalNotes  int     `json:"total_notes"`
+	NotesPerRow float64 `json:"notes_per_row"`
+
+	Channels          []ChannelStats    `json:"channels"`
+	Instruments       []InstrumentStats `json:"instruments"`        // Used instruments (or samples), ascending
+	UnusedInstruments []int             `json:"unused_instruments"` // Never triggered by the order list
+	Effects           []EffectStats     `json:"effects"`            // Most used first
+}
+
+// ChannelStats is the note range of one channel
+type ChannelStats struct {
+	Channel     int    `json:"channel"` // 1-based, as trackers number them
+	Notes       int    `json:"notes"`
+	LowestNote  int    `json:"lowest_note,omitempty"` // libopenmpt note value (C-0 = 1)
+	HighestNote int    `json:"highest_note,omitempty"`
+	Lowest      string `json:"lowest,omitempty"` // Note names as the tracker shows them
+	Highest     string `json:"highest,omitempty"`
+}
+
+// InstrumentStats counts how often an instrument (or sample, in sample-based formats) is triggered
+type InstrumentStats struct {
+	Instrument int    `json:"instrument"`
+	Name       string `json:"name"`
+	Uses       int    `json:"uses"`
+	Channels   []int  `json:"channels"` // 1-based channels it appears on
+}
+
+// EffectStats counts one effect command in the module's own notation
+type EffectStats struct {
+	Effect string `json:"effect"`
+	Count  int    `json:"count"`
+}
+
+// GetStats walks the order list over the pattern cache and tallies notes, instruments and effects
+func (m *Module) GetStats() SongStats {
+	numPatterns := m.GetNumPatterns()
+	numChannels := m.GetNumChannels()
+	orders := m.GetOrderList()
+
+	// Formats with instruments reference them in the instrument column; others reference samples
+	names := m.GetRawInstrumentNames()
+	if len(names) == 0 {
+		names = m.GetRawSampleNames()
+	}
+
+	stats := SongStats{
+		NumPatterns: numPatterns,
+		Channels:    make([]ChannelStats, numChannels),
+	}
+	for ch := range stats.Channels {
+		stats.Channels[ch].Channel = ch + 1
+	}
+
+	referenced := make(map[int]bool)
+	instrumentUses := make(map[int]int)
+	instrumentChannels := make(map[int]map[int]bool)
+	effectCounts := make(map[string]int)
+
+	for _, pattern := range orders {
+		if pattern == OrderSkip || pattern == OrderEnd {
+			continue // Later subsongs sit past "---" markers
+		}
+		referenced[pattern] = true
+
+		for _, row := range m.GetCachedPattern(pattern).Rows {
+			stats.OrderRows++
+			for ch, cell := range row.Channels {
+				if ch >= numChannels {
+					break
+				}
+
+				if cell.Note >= NoteMin && cell.Note <= NoteMax {
+					stats.TotalNotes++
+					c := &stats.Channels[ch]
+					c.Notes++
+					if c.LowestNote == 0 || cell.Note < c.LowestNote {
+						c.LowestNote, c.Lowest = cell.Note, cellNoteName(cell)
+					}
+					if cell.Note > c.HighestNote {
+						c.HighestNote, c.Highest = cell.Note, cellNoteName(cell)
+					}
+				}
+
+				if cell.Instrument > 0 {
+					instrumentUses[cell.Instrument]++
+					if instrumentChannels[cell.Instrument] == nil {
+						instrumentChannels[cell.Instrument] = make(map[int]bool)
+					}
+					instrumentChannels[cell.Instrument][ch+1] = true
+				}
+
+				if len(cell.Text) > CellEffectAt {
+					if effect := cell.Text[CellEffectAt : CellEffectAt+1]; effect != "." && effect != " " {
+						effectCounts[effect]++
+					}
+				}
+			}
+		}
+	}
+
+	if stats.OrderRows > 0 {
+		stats.NotesPerRow = float64(stats.TotalNotes) / float64(stats.OrderRows)
+	}
+
+	for pattern := 0; pattern < numPatterns; pattern++ {
+		// Patterns with no rows are empty slots, not unused content
+		if referenced[pattern] {
+			stats.ReferencedPatterns = append(stats.ReferencedPatterns, pattern)
+		} else if m.GetPatternNumRows(pattern) > 0 {
+			stats.UnusedPatterns = append(stats.UnusedPatterns, pattern)
+		}
+	}
+
+	for i, name := range names {
+		instrument := i + 1
+		uses := instrumentUses[instrument]
+		if uses == 0 {
+			stats.UnusedInstruments = append(stats.UnusedInstruments, instrument)
+			continue
+		}
+		channels := make([]int, 0, len(instrumentChannels[instrument]))
+		for ch := range instrumentChannels[instrument] {
+			channels = append(channels, ch)
+		}
+		sort.Ints(channels)
+		stats.Instruments = append(stats.Instruments, InstrumentStats{
+			Instrument: instrument,
+			Name:       name,
+			Uses:       uses,
+			Channels:   channels,
+		})
+	}
+
+	for effect, count := range effectCounts {
+		stats.Effects = append(stats.Effects, EffectStats{Effect: effect, Count: count})
+	}
+	sort.Slice(stats.Effects, func(i, j int) bool {
+		if stats.Effects[i].Count != stats.Effects[j].Count {
+			return stats.Effects[i].Count > stats.Effects[j].Count
+		}
+		return stats.Effects[i].Effect < stats.Effects[j].Effect
+	})
+
+	return stats
+}
+
+// cellNoteName returns the note as the tracker writes it
+func cellNoteName(cell PatternCell) string {
+	if len(cell.Text) >= CellNoteAt+CellNoteLen {
+		return cell.Text[CellNoteAt : CellNoteAt+CellNoteLen]
+	}
+	return ""
+}
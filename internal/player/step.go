@@ -0,0 +1,208 @@
+package player
+
+import "time"
+
+const (
+	// stepChunkFrames is how much of a stepped row is rendered per Module.Read
+	stepChunkFrames = 256
+	// stepMaxSeconds bounds the audio of one stepped row (very slow speeds, pattern delays)
+	stepMaxSeconds = 2
+)
+
+// StepRow moves the paused position by delta rows and plays the row stepped into once
+// Returns false unless paused (or ended): stepping is a paused-transport feature
+func (p *Player) StepRow(delta int) bool {
+	return p.step(delta, 0)
+}
+
+// StepTick moves the paused position by delta ticks and plays just that tick
+// Stepping past either end of a row carries into the neighbouring row
+func (p *Player) StepTick(delta int) bool {
+	return p.step(0, delta)
+}
+
+func (p *Player) step(rows, ticks int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.otoPlayer == nil || p.module == nil {
+		return false
+	}
+	if st := p.State(); st != TransportPaused && st != TransportEnded {
+		return false
+	}
+
+	p.queueMu.Lock()
+	order, row, tick := p.held.Order, p.held.Row, p.held.Tick
+	p.queueMu.Unlock()
+
+	// Stop pulling audio before the module is moved; Reset also drops any earlier step
+	p.otoPlayer.Reset()
+
+	orders := p.module.GetOrderList()
+	if rows != 0 {
+		order, row = neighbourRow(p.module, orders, order, row, rows)
+		tick = 0
+	}
+	tick += ticks
+
+	pcm, state, seconds, speed := p.renderRow(order, row)
+	switch {
+	case tick >= speed:
+		if nextOrder, nextRow := neighbourRow(p.module, orders, order, row, 1); nextOrder != order || nextRow != row {
+			order, row, tick = nextOrder, nextRow, 0
+			pcm, state, seconds, speed = p.renderRow(order, row)
+		} else {
+			tick = speed - 1 // End of the song
+		}
+	case tick < 0:
+		if prevOrder, prevRow := neighbourRow(p.module, orders, order, row, -1); prevOrder != order || prevRow != row {
+			order, row = prevOrder, prevRow
+			pcm, state, seconds, speed = p.renderRow(order, row)
+			tick = speed - 1
+		} else {
+			tick = 0 // Start of the song
+		}
+	}
+
+	if ticks != 0 && speed > 0 {
+		// Ticks split the row's audio evenly
+		frames := len(pcm) / channelCount
+		from, to := frames*tick/speed, frames*(tick+1)/speed
+		pcm = pcm[from*channelCount : to*channelCount]
+		seconds += float64(from) / sampleRate
+	}
+	state.Tick = tick
+
+	p.queueMu.Lock()
+	p.held = state
+	p.heldTime = seconds
+	p.stepping = true
+	p.stepBuf = pcm
+	p.pauseFade = false
+	p.env.rampTo(0, 0) // Resuming fades in from silence
+	p.stateQueue = p.stateQueue[:0]
+	p.queueMu.Unlock()
+
+	p.state.Store(int32(TransportPaused))
+	p.otoPlayer.Play()
+	return true
+}
+
+// renderRow seeks to order/row and renders until playback leaves the row (bounded by stepMaxSeconds)
+// Returns the audio, the row's state with VUs from its start, its start time and its speed
+func (p *Player) renderRow(order, row int) ([]int16, SyncState, float64, int) {
+	seconds := p.module.SetPositionOrderRow(order, row)
+	state := SyncState{
+		Order:   p.module.GetCurrentOrder(),
+		Row:     p.module.GetCurrentRow(),
+		Pattern: p.module.GetCurrentPattern(),
+	}
+	speed := 0
+
+	buf := make([]int16, stepChunkFrames*channelCount)
+	pcm := make([]int16, 0, sampleRate*channelCount/4)
+	for len(pcm) < stepMaxSeconds*sampleRate*channelCount {
+		frames := p.module.Read(buf)
+		if frames == 0 {
+			break // End of the song
+		}
+		pcm = append(pcm, buf[:frames*channelCount]...)
+
+		if state.ChannelVolumes == nil {
+			// The row's own Fxx/speed commands and note-ons have been processed by now
+			speed = p.module.GetCurrentSpeed()
+			vus := p.module.GetChannelVUs()
+			state.ChannelVolumes, state.ChannelLeft, state.ChannelRight = vus.Mono, vus.Left, vus.Right
+		}
+		if p.module.GetCurrentOrder() != state.Order || p.module.GetCurrentRow() != state.Row {
+			break
+		}
+	}
+	return pcm, state, seconds, max(1, speed)
+}
+
+// neighbourRow walks delta rows through the order list, skipping "+++" markers
+// It stops at the start of the song and at its end ("---" or the last order)
+func neighbourRow(m *Module, orders []int, order, row, delta int) (int, int) {
+	if order < 0 || order >= len(orders) {
+		return order, row
+	}
+	for ; delta > 0; delta-- {
+		if row+1 < m.GetPatternNumRows(orders[order]) {
+			row++
+			continue
+		}
+		next := playableOrder(orders, order, 1)
+		if next < 0 {
+			break
+		}
+		order, row = next, 0
+	}
+	for ; delta < 0; delta++ {
+		if row > 0 {
+			row--
+			continue
+		}
+		prev := playableOrder(orders, order, -1)
+		if prev < 0 {
+			break
+		}
+		order, row = prev, max(0, m.GetPatternNumRows(orders[prev])-1)
+	}
+	return order, row
+}
+
+// playableOrder returns the next order in direction dir that holds a pattern, or -1
+func playableOrder(orders []int, order, dir int) int {
+	for o := order + dir; o >= 0 && o < len(orders); o += dir {
+		switch orders[o] {
+		case OrderSkip:
+			continue
+		case OrderEnd:
+			if dir > 0 {
+				return -1
+			}
+			continue
+		}
+		return o
+	}
+	return -1
+}
+
+// readHeld serves step audio, then silence, once a pause has faded out or playback has ended
+// Returns false otherwise, so the caller renders the module as usual
+func (r *audioReader) readHeld(out []byte) (int, bool) {
+	p := r.player
+	p.queueMu.Lock()
+	if st := p.State(); (st != TransportPaused || p.pauseFade) && st != TransportEnded {
+		p.queueMu.Unlock()
+		return 0, false
+	}
+	if !p.fade.sleepAt.IsZero() && time.Now().After(p.fade.sleepAt) {
+		// Nothing to fade out: the sleep timer ran out while paused
+		p.fade.sleepAt = time.Time{}
+	}
+
+	samples := min(len(out)/2, len(r.buf))
+	samples -= samples % channelCount
+	n := copy(r.buf[:samples], p.stepBuf)
+	clear(r.buf[n:samples])
+	p.stepBuf = p.stepBuf[n:]
+	if len(p.stepBuf) == 0 {
+		// The step has gone to the device; the meters fall with the silence after it
+		p.held.ChannelVolumes, p.held.ChannelLeft, p.held.ChannelRight = nil, nil, nil
+	}
+
+	start := p.samplesWritten
+	p.samplesWritten += int64(samples / channelCount)
+	p.queueMu.Unlock()
+
+	p.tap.write(start, r.buf[:samples])
+
+	for i := 0; i < samples; i++ {
+		out[i*2] = byte(r.buf[i] & 0xff)
+		out[i*2+1] = byte((r.buf[i] >> 8) & 0xff)
+	}
+	return samples * 2, true
+}
@@ -0,0 +1,91 @@
+package player
+
+import "sync"
+
+// tapCapacity is how many frames the output tap remembers (~370ms at 44.1kHz)
+// It must cover the device buffer plus the largest analysis window the UI asks for
+const tapCapacity = 16384
+
+// OutputTap keeps the most recently rendered PCM, addressable by absolute sample position
+// Positions use the same clock as SyncState.SampleCount, so the UI can read exactly
+// the audio the hardware is playing right now
+type OutputTap struct {
+	mu    sync.Mutex
+	left  []float32
+	right []float32
+	end   int64 // Sample position one past the newest frame
+}
+
+func newOutputTap(capacity int) *OutputTap {
+	return &OutputTap{
+		left:  make([]float32, capacity),
+		right: make([]float32, capacity),
+	}
+}
+
+// write stores interleaved stereo frames that start at sample position pos
+func (t *OutputTap) write(pos int64, interleaved []int16) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// A seek moves the clock; drop stale audio rather than splice it in
+	if pos != t.end {
+		t.clearLocked()
+	}
+
+	size := int64(len(t.left))
+	frames := len(interleaved) / 2
+	for i := 0; i < frames; i++ {
+		idx := (pos + int64(i)) % size
+		if idx < 0 {
+			idx += size
+		}
+		t.left[idx] = float32(interleaved[i*2]) / 32768
+		t.right[idx] = float32(interleaved[i*2+1]) / 32768
+	}
+	t.end = pos + int64(frames)
+}
+
+// reset forgets all buffered audio
+func (t *OutputTap) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clearLocked()
+	t.end = 0
+}
+
+func (t *OutputTap) clearLocked() {
+	for i := range t.left {
+		t.left[i] = 0
+		t.right[i] = 0
+	}
+}
+
+// Window copies the len(left) frames that end at sample position end into left and right
+// Frames that were never rendered (or already overwritten) read as silence
+// Returns the number of frames that held real audio
+func (t *OutputTap) Window(end int64, left, right []float32) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := len(left)
+	if len(right) < n {
+		n = len(right)
+	}
+	size := int64(len(t.left))
+	oldest := t.end - size
+
+	valid := 0
+	for i := 0; i < n; i++ {
+		pos := end - int64(n) + int64(i)
+		if pos < 0 || pos < oldest || pos >= t.end {
+			left[i], right[i] = 0, 0
+			continue
+		}
+		idx := pos % size
+		left[i] = t.left[idx]
+		right[i] = t.right[idx]
+		valid++
+	}
+	return valid
+}
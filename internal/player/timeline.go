@@ -0,0 +1,78 @@
+package player
+
+/*
+#cgo pkg-config: libopenmpt
+#include <libopenmpt/libopenmpt.h>
+*/
+import "C"
+import "unsafe"
+
+const (
+	// timelineRate is the render rate used to walk the song; audio quality doesn't matter here
+	timelineRate = 8000
+	// timelineChunk frames per read gives 2ms row-start resolution at timelineRate
+	timelineChunk = 16
+)
+
+// PlayedRow is one row as playback actually reaches it
+type PlayedRow struct {
+	Order   int
+	Pattern int
+	Row     int
+	Start   float64 // Seconds from the start of the song
+	Speed   int     // Ticks per row at this row
+	Tempo   int
+}
+
+// Timeline plays the song silently from the start and records every row in the order it is reached
+// Speed and tempo changes, pattern breaks, jumps and loops are followed exactly as the player
+// would. Also returns the song length in seconds, where the last row ends.
+// The module is rewound afterwards, so call this before (or instead of) playing it.
+func (m *Module) Timeline() ([]PlayedRow, float64) {
+	if m == nil {
+		return nil, 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mod == nil {
+		return nil, 0
+	}
+
+	// Play the song once through, then stop
+	repeat := C.openmpt_module_get_repeat_count(m.mod)
+	C.openmpt_module_set_repeat_count(m.mod, 0)
+	C.openmpt_module_set_position_seconds(m.mod, 0)
+	defer func() {
+		C.openmpt_module_set_repeat_count(m.mod, repeat)
+		C.openmpt_module_set_position_seconds(m.mod, 0)
+	}()
+
+	var rows []PlayedRow
+	var buf [timelineChunk]int16
+	frames := 0
+	lastOrder, lastRow := -1, -1
+
+	for {
+		order := int(C.openmpt_module_get_current_order(m.mod))
+		row := int(C.openmpt_module_get_current_row(m.mod))
+		if order != lastOrder || row != lastRow {
+			rows = append(rows, PlayedRow{
+				Order:   order,
+				Pattern: int(C.openmpt_module_get_current_pattern(m.mod)),
+				Row:     row,
+				Start:   float64(frames) / timelineRate,
+				Speed:   int(C.openmpt_module_get_current_speed(m.mod)),
+				Tempo:   int(C.openmpt_module_get_current_tempo(m.mod)),
+			})
+			lastOrder, lastRow = order, row
+		}
+
+		n := int(C.openmpt_module_read_mono(m.mod, timelineRate, timelineChunk, (*C.int16_t)(unsafe.Pointer(&buf[0]))))
+		if n == 0 {
+			break
+		}
+		frames += n
+	}
+
+	return rows, float64(frames) / timelineRate
+}
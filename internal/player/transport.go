@@ -0,0 +1,94 @@
+package player
+
+import "math"
+
+// TransportState is where the player's transport is
+// The audio reader moves Loading/Seeking to Playing once audio from the new position is rendered,
+// and Playing to Ended when the module runs out
+type TransportState int32
+
+const (
+	TransportStopped TransportState = iota // Created, Play not called yet
+	TransportLoading                       // Play called, no audio rendered yet
+	TransportPlaying
+	TransportPaused // Holding the last heard position; row steps allowed
+	TransportEnded  // The module finished; the last position is held
+	TransportSeeking
+)
+
+func (s TransportState) String() string {
+	switch s {
+	case TransportLoading:
+		return "LOADING"
+	case TransportPlaying:
+		return "PLAYING"
+	case TransportPaused:
+		return "PAUSED"
+	case TransportEnded:
+		return "ENDED"
+	case TransportSeeking:
+		return "SEEKING"
+	}
+	return "STOPPED"
+}
+
+// Symbol is a one-character icon for the state, for badges
+func (s TransportState) Symbol() string {
+	switch s {
+	case TransportLoading, TransportSeeking:
+		return "…"
+	case TransportPlaying:
+		return "▶"
+	case TransportPaused:
+		return "‖"
+	}
+	return "■"
+}
+
+// pauseFadeFrames is the fade applied when pausing and resuming (~20ms), so neither clicks
+const pauseFadeFrames = sampleRate / 50
+
+// envelope is a linear gain ramp applied to rendered audio
+type envelope struct {
+	gain   float64
+	target float64
+	step   float64 // Gain change per frame
+}
+
+// newEnvelope starts at full volume
+func newEnvelope() envelope {
+	return envelope{gain: 1, target: 1}
+}
+
+// rampTo moves the gain to target over frames
+func (e *envelope) rampTo(target float64, frames int) {
+	e.target = target
+	if frames <= 0 {
+		e.gain, e.step = target, 0
+		return
+	}
+	e.step = (target - e.gain) / float64(frames)
+}
+
+// done reports whether the ramp has reached its target
+func (e *envelope) done() bool {
+	return e.gain == e.target
+}
+
+// apply scales interleaved stereo samples, advancing the ramp one step per frame
+func (e *envelope) apply(buf []int16) {
+	if e.done() && e.gain == 1 {
+		return
+	}
+	for i := 0; i+1 < len(buf); i += channelCount {
+		if !e.done() {
+			e.gain += e.step
+			if (e.step > 0 && e.gain >= e.target) || (e.step < 0 && e.gain <= e.target) || e.step == 0 {
+				e.gain = e.target
+			}
+		}
+		for c := 0; c < channelCount; c++ {
+			buf[i+c] = int16(math.Round(float64(buf[i+c]) * e.gain))
+		}
+	}
+}
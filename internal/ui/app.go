@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/slimewell/GoMod/internal/player"
 
 	"github.com/charmbracelet/lipgloss"
@@ -25,15 +27,15 @@ type AppModel struct {
 	audioContext *oto.Context
 
 	// Global config to persist across module loads
-	stereoSep int
-	themeName string
+	config Config
 
 	width  int
 	height int
 }
 
 // NewModel creates the main application model
-func NewModel(filename string, stereoSep int, themeName string) (AppModel, error) {
+// A zero source opens the file browser instead of playing
+func NewModel(source ModuleSource, config Config) (AppModel, error) {
 	// Initialize audio context once
 	ac, err := player.NewAudioContext()
 	if err != nil {
@@ -46,9 +48,9 @@ func NewModel(filename string, stereoSep int, themeName string) (AppModel, error
 	var state AppState
 	var pm *PlayerModel
 
-	if filename != "" {
+	if !source.IsZero() {
 		state = StatePlaying
-		pm = NewPlayerModel(ac, filename, stereoSep, themeName, w, h)
+		pm = NewPlayerModel(ac, source, config, w, h)
 	} else {
 		state = StateBrowsing
 		// Player is nil initially
@@ -59,8 +61,7 @@ func NewModel(filename string, stereoSep int, themeName string) (AppModel, error
 		playerModel:  pm,
 		browserModel: NewFileBrowserModel(w, h),
 		audioContext: ac,
-		stereoSep:    stereoSep,
-		themeName:    themeName,
+		config:       config,
 		width:        w,
 		height:       h,
 	}, nil
@@ -82,6 +83,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// While the player takes text input, only ctrl+c stays global
+		if m.state == StatePlaying && m.playerModel != nil && m.playerModel.Typing() && msg.String() != "ctrl+c" {
+			break
+		}
+
 		// Global Key Handling
 		switch msg.String() {
 		case "q", "ctrl+c":
@@ -97,19 +103,20 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.playerModel != nil {
 				if m.state == StateBrowsing {
 					m.state = StatePlaying
-				} else {
-					m.state = StateBrowsing
-					// Update browser size just in case
-					m.browserModel.width = m.width
-					m.browserModel.height = m.height
+					return m, m.playerModel.mouseCmd()
 				}
-				return m, nil
+				m.state = StateBrowsing
+				// Update browser size just in case
+				m.browserModel.width = m.width
+				m.browserModel.height = m.height
+				// The browser has nothing to click, so leave text selection to the terminal
+				return m, tea.DisableMouse
 			}
 		
 		case "esc":
 			if m.state == StateBrowsing && m.playerModel != nil {
 				m.state = StatePlaying
-				return m, nil
+				return m, m.playerModel.mouseCmd()
 			}
 		}
 
@@ -139,10 +146,10 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.browserModel = newBrowser.(*FileBrowserModel)
 		cmds = append(cmds, cmd)
 
-        // Fix Frozen UI: Update Player background (Ticks only)
+        // Fix Frozen UI: Update Player background (ticks and background loading only)
         if m.playerModel != nil {
              switch msg.(type) {
-             case tickMsg:
+             case tickMsg, moduleLoadedMsg, songViewsMsg:
                  newPlayer, pCmd := m.playerModel.Update(msg)
                  m.playerModel = newPlayer.(*PlayerModel)
                  cmds = append(cmds, pCmd)
@@ -155,9 +162,12 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.browserModel.Selected = "" // Reset
 
 			// If we had a player, close it
-			if m.playerModel != nil {
-				// Save state
-				m.stereoSep = m.playerModel.stereoSep
+			hadPlayer := m.playerModel != nil
+			var sleepAt time.Time
+			if hadPlayer {
+				// Save state; the sleep timer keeps running across modules
+				m.config.StereoSep = m.playerModel.stereoSep
+				sleepAt = m.playerModel.sleepDeadline()
 				
 				// Close old player
 				m.playerModel.Close()
@@ -165,7 +175,10 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Create new player with current config and SHARED AUDIO CONTEXT
-			m.playerModel = NewPlayerModel(m.audioContext, filename, m.stereoSep, m.themeName, m.width, m.height)
+			m.playerModel = NewPlayerModel(m.audioContext, FileSource(filename), m.config, m.width, m.height)
+			if hadPlayer {
+				m.playerModel.sleepAt = sleepAt
+			}
 			m.state = StatePlaying
 			
 			cmds = append(cmds, m.playerModel.Init())
@@ -217,6 +230,14 @@ func (m AppModel) View() string {
 	return "No module loaded."
 }
 
+// Err returns the error that stopped playback (e.g. a failed load), if any
+func (m AppModel) Err() error {
+	if m.playerModel == nil {
+		return nil
+	}
+	return m.playerModel.err
+}
+
 // errMsg is shared within the ui package
 type errMsg struct {
 	err error
@@ -0,0 +1,65 @@
+package ui
+
+import "strings"
+
+// brailleCanvas is a dot grid drawn with Unicode braille characters (2×4 dots per cell)
+type brailleCanvas struct {
+	cols, rows int
+	cells      []uint8 // One dot bitmask per character cell
+}
+
+// brailleDots maps a dot position inside a cell ([x][y]) to its bit in U+2800..U+28FF
+var brailleDots = [2][4]uint8{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+func newBrailleCanvas(cols, rows int) *brailleCanvas {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return &brailleCanvas{
+		cols:  cols,
+		rows:  rows,
+		cells: make([]uint8, cols*rows),
+	}
+}
+
+// width and height in dots
+func (c *brailleCanvas) width() int  { return c.cols * 2 }
+func (c *brailleCanvas) height() int { return c.rows * 4 }
+
+// set lights the dot at (x, y); out-of-range dots are ignored
+func (c *brailleCanvas) set(x, y int) {
+	if x < 0 || y < 0 || x >= c.width() || y >= c.height() {
+		return
+	}
+	c.cells[(y/4)*c.cols+x/2] |= brailleDots[x%2][y%4]
+}
+
+// vline lights a vertical run of dots between y0 and y1 (inclusive, either order)
+func (c *brailleCanvas) vline(x, y0, y1 int) {
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	for y := y0; y <= y1; y++ {
+		c.set(x, y)
+	}
+}
+
+// lines returns one string per character row
+func (c *brailleCanvas) lines() []string {
+	out := make([]string, c.rows)
+	var b strings.Builder
+	for r := 0; r < c.rows; r++ {
+		b.Reset()
+		for col := 0; col < c.cols; col++ {
+			b.WriteRune(rune(0x2800 + int(c.cells[r*c.cols+col])))
+		}
+		out[r] = b.String()
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// Steps for the focused channel's volume and panning keys
+	channelVolumeStep  = 0.1
+	channelPanningStep = 0.1
+)
+
+// ChannelWindow is the range of channels shown side by side, plus the focused one
+type ChannelWindow struct {
+	First int // First visible channel
+	Count int // Number of visible channels
+	Total int // Channels in the module
+	Focus int // Focused channel (absolute index)
+
+	// Density is the resolved column layout (never DensityAuto)
+	Density PatternDensity
+
+	// FocusMix describes the focused channel's volume and panning, e.g. "80% L30"
+	FocusMix string
+}
+
+// Last returns the index after the last visible channel
+func (w ChannelWindow) Last() int {
+	return min(w.First+w.Count, w.Total)
+}
+
+// Scrolled reports whether some channels are off screen
+func (w ChannelWindow) Scrolled() bool {
+	return w.First > 0 || w.Last() < w.Total
+}
+
+// PatternDensity selects which parts of each pattern cell are shown
+type PatternDensity int
+
+const (
+	DensityAuto    PatternDensity = iota // Widest layout that fits every channel
+	DensityFull                          // Note, instrument, volume and effect: "C-5 01 v40 A0F"
+	DensityCompact                       // Note and instrument: "C-5 01"
+	DensityNotes                         // Note only: "C-5"
+	densityCount
+)
+
+// next cycles through the density modes, including auto
+func (d PatternDensity) next() PatternDensity {
+	return (d + 1) % densityCount
+}
+
+func (d PatternDensity) String() string {
+	switch d {
+	case DensityFull:
+		return "full"
+	case DensityCompact:
+		return "compact"
+	case DensityNotes:
+		return "notes"
+	}
+	return "auto"
+}
+
+// ParsePatternDensity reads a density by the name String gives it
+func ParsePatternDensity(s string) (PatternDensity, error) {
+	for d := PatternDensity(0); d < densityCount; d++ {
+		if d.String() == s {
+			return d, nil
+		}
+	}
+	return DensityAuto, fmt.Errorf("unknown density %q (want auto, full, compact or notes)", s)
+}
+
+// cellWidth is the width of one cell in this layout (prefixes of the full libopenmpt cell)
+func (d PatternDensity) cellWidth() int {
+	switch d {
+	case DensityCompact:
+		return 6
+	case DensityNotes:
+		return 3
+	}
+	return fullCellWidth
+}
+
+// columnWidth is one pattern/VU column: a cell plus its " │" separator
+func (d PatternDensity) columnWidth() int {
+	return d.cellWidth() + 2
+}
+
+// resolveDensity picks the widest layout that shows every channel for DensityAuto
+// When even notes-only can't fit them all, notes-only still shows the most
+func resolveDensity(d PatternDensity, width, channels int) PatternDensity {
+	if d != DensityAuto {
+		return d
+	}
+	for _, candidate := range []PatternDensity{DensityFull, DensityCompact} {
+		if channelsThatFit(width, candidate) >= channels {
+			return candidate
+		}
+	}
+	return DensityNotes
+}
+
+// channelsThatFit returns how many channel columns fit beside the row column and pattern info
+func channelsThatFit(width int, density PatternDensity) int {
+	// "Row │" on the left, " Pat: XX" after the current row
+	available := width - 6 - 8
+	return max(1, available/density.columnWidth())
+}
+
+// channelLabel is the header text for a channel: its name if it has one, else its number
+func channelLabel(channel int, names []string, width int) string {
+	if channel < len(names) {
+		if name := strings.TrimSpace(names[channel]); name != "" {
+			return name
+		}
+	}
+	if width < 5 {
+		return fmt.Sprintf("%d", channel+1)
+	}
+	return fmt.Sprintf("Ch%d", channel+1)
+}
+
+// scrollChannelWindow returns the first visible channel that keeps focus on screen
+// The window only moves when the focus would leave it
+func scrollChannelWindow(first, focus, count, total int) int {
+	if focus < first {
+		first = focus
+	}
+	if focus >= first+count {
+		first = focus - count + 1
+	}
+	// Don't leave empty columns at the end when scrolled
+	first = min(first, total-count)
+	return max(0, first)
+}
+
+// windowFloats returns the visible part of a per-channel slice
+func windowFloats(values []float64, w ChannelWindow) []float64 {
+	first := min(w.First, len(values))
+	last := min(w.Last(), len(values))
+	return values[first:last]
+}
+
+// windowBools returns the visible part of a per-channel slice
+func windowBools(values []bool, w ChannelWindow) []bool {
+	first := min(w.First, len(values))
+	last := min(w.Last(), len(values))
+	return values[first:last]
+}
+
+// formatChannelMix describes a channel's volume and panning, e.g. "80% L30" or "100% C"
+func formatChannelMix(volume, panning float64) string {
+	pan := "C"
+	amount := int(math.Round(math.Abs(panning) * 100))
+	if amount > 0 {
+		side := "R"
+		if panning < 0 {
+			side = "L"
+		}
+		pan = fmt.Sprintf("%s%d", side, amount)
+	}
+	return fmt.Sprintf("%d%% %s", int(math.Round(volume*100)), pan)
+}
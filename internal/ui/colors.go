@@ -204,3 +204,28 @@ func GetPalette(name string) ColorPalette {
 		return DefaultPalette()
 	}
 }
+
+// channelColors are distinct hues used to tell channels apart in note visualizations
+// They are shared by every theme, since the palettes are mostly monochrome
+var channelColors = []lipgloss.Color{
+	"#F87171", // Red
+	"#FBBF24", // Amber
+	"#A3E635", // Lime
+	"#34D399", // Emerald
+	"#22D3EE", // Cyan
+	"#60A5FA", // Blue
+	"#A78BFA", // Violet
+	"#F472B6", // Pink
+	"#FB923C", // Orange
+	"#4ADE80", // Green
+	"#38BDF8", // Sky
+	"#E879F9", // Fuchsia
+}
+
+// ChannelColor returns the visualization color for a channel (0-based)
+func ChannelColor(channel int) lipgloss.Color {
+	if channel < 0 {
+		channel = 0
+	}
+	return channelColors[channel%len(channelColors)]
+}
@@ -4,21 +4,77 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/slimewell/GoMod/internal/player"
 )
 
 // Config holds persistent user preferences
 type Config struct {
-	Theme     string `json:"theme"`
-	StereoSep int    `json:"stereo_separation"`
-	LastUsed  string `json:"last_file,omitempty"`
+	Theme           string `json:"theme"`
+	StereoSep       int    `json:"stereo_separation"`
+	LastUsed        string `json:"last_file,omitempty"`
+	SpectrumBands   int    `json:"spectrum_bands,omitempty"`
+	VUDecayMs       int    `json:"vu_decay_ms,omitempty"`
+	VUPeakHoldMs    int    `json:"vu_peak_hold_ms,omitempty"`
+	FadeMs          *int   `json:"fade_ms,omitempty"` // nil = DefaultFadeMs, 0 = stop without fading
+
+	// The loop and duration limits and the sleep timer are per-session choices, so they aren't saved
+	Loops       int           `json:"-"`
+	MaxDuration time.Duration `json:"-"`
+	Sleep       time.Duration `json:"-"`
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() Config {
 	return Config{
-		Theme:     "default",
-		StereoSep: 50,
+		Theme:         "default",
+		StereoSep:     50,
+		SpectrumBands: DefaultSpectrumBands,
+		VUDecayMs:     DefaultVUDecayMs,
+		VUPeakHoldMs:  DefaultVUPeakHoldMs,
+	}
+}
+
+// vuDecay returns the VU release time constant, falling back to the default
+func (c Config) vuDecay() time.Duration {
+	if c.VUDecayMs <= 0 {
+		return DefaultVUDecayMs * time.Millisecond
+	}
+	return time.Duration(c.VUDecayMs) * time.Millisecond
+}
+
+// vuPeakHold returns how long VU peak markers hold, falling back to the default
+func (c Config) vuPeakHold() time.Duration {
+	if c.VUPeakHoldMs <= 0 {
+		return DefaultVUPeakHoldMs * time.Millisecond
+	}
+	return time.Duration(c.VUPeakHoldMs) * time.Millisecond
+}
+
+// FadeLength returns the fade-out length, falling back to the default when none is saved
+func (c Config) FadeLength() time.Duration {
+	if c.FadeMs == nil || *c.FadeMs < 0 {
+		return DefaultFadeMs * time.Millisecond
+	}
+	return time.Duration(*c.FadeMs) * time.Millisecond
+}
+
+// fadeOptions returns the loop and duration limits with the fade length
+func (c Config) fadeOptions() player.FadeOptions {
+	return player.FadeOptions{
+		Loops:       c.Loops,
+		MaxDuration: c.MaxDuration,
+		Fade:        c.FadeLength(),
+	}
+}
+
+// spectrumBands returns the configured band count, falling back to the default
+func (c Config) spectrumBands() int {
+	if c.SpectrumBands <= 0 {
+		return DefaultSpectrumBands
 	}
+	return c.SpectrumBands
 }
 
 // configPath returns the path to the config file
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+// DumpFormat is a plain-text layout for pattern data
+type DumpFormat int
+
+const (
+	DumpASCII     DumpFormat = iota // The pattern view's grid, without colors
+	DumpClipboard                   // OpenMPT's clipboard text, pasteable into OpenMPT and compatible trackers
+)
+
+// ParseDumpFormat understands "ascii" and "openmpt" (or "clipboard")
+func ParseDumpFormat(s string) (DumpFormat, error) {
+	switch s {
+	case "ascii", "text":
+		return DumpASCII, nil
+	case "openmpt", "clipboard", "mpt":
+		return DumpClipboard, nil
+	}
+	return DumpASCII, fmt.Errorf("unknown dump format %q (want ascii or openmpt)", s)
+}
+
+// DumpRange selects part of a pattern; ends are inclusive and -1 means "to the end"
+type DumpRange struct {
+	FirstRow, LastRow         int
+	FirstChannel, LastChannel int // 0-based
+}
+
+// WholePattern selects every row and channel
+var WholePattern = DumpRange{LastRow: -1, LastChannel: -1}
+
+// clipboardFormats is the format tag OpenMPT writes after "ModPlug Tracker ", by libopenmpt's short type
+// Formats OpenMPT converts on load (669, MTM, ...) are pasted as IT, the most permissive of them
+var clipboardFormats = map[string]string{
+	"mod":  "MOD",
+	"xm":   " XM",
+	"s3m":  "S3M",
+	"it":   " IT",
+	"mptm": "MPT",
+}
+
+// DumpPattern renders a pattern (or part of one) as text in the given format
+// density only applies to DumpASCII; DensityAuto means full cells.
+func DumpPattern(mod *player.Module, pattern int, rng DumpRange, format DumpFormat, density PatternDensity) string {
+	rows := mod.GetCachedPattern(pattern).Rows
+	numChannels := mod.GetNumChannels()
+
+	lastRow := rng.LastRow
+	if lastRow < 0 || lastRow >= len(rows) {
+		lastRow = len(rows) - 1
+	}
+	lastChannel := rng.LastChannel
+	if lastChannel < 0 || lastChannel >= numChannels {
+		lastChannel = numChannels - 1
+	}
+	firstRow, firstChannel := max(0, rng.FirstRow), max(0, rng.FirstChannel)
+	if firstRow > lastRow || firstChannel > lastChannel {
+		return ""
+	}
+	rows = rows[firstRow : lastRow+1]
+
+	if format == DumpClipboard {
+		return dumpClipboard(rows, firstChannel, lastChannel, mod.GetMetadata().Format)
+	}
+	if density == DensityAuto {
+		density = DensityFull
+	}
+	return dumpASCII(rows, firstRow, firstChannel, lastChannel, mod.GetChannelNames(), density)
+}
+
+// dumpASCII mirrors RenderPattern's layout: row numbers, channel labels and │ separators
+func dumpASCII(rows []player.PatternRow, firstRow, firstChannel, lastChannel int, channelNames []string, density PatternDensity) string {
+	cellWidth := density.cellWidth()
+
+	var b strings.Builder
+	header := fmt.Sprintf("%4s │", "Row")
+	for ch := firstChannel; ch <= lastChannel; ch++ {
+		header += fitWidth(channelLabel(ch, channelNames, cellWidth), cellWidth) + " │"
+	}
+	b.WriteString(header + "\n")
+	b.WriteString(strings.Repeat("─", len([]rune(header))) + "\n")
+
+	for i, row := range rows {
+		fmt.Fprintf(&b, "%04X │", firstRow+i)
+		for ch := firstChannel; ch <= lastChannel; ch++ {
+			cell := player.PatternCell{}
+			if ch < len(row.Channels) {
+				cell = row.Channels[ch]
+			}
+			b.WriteString(cellText(cell, cellWidth) + " │")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// dumpClipboard writes OpenMPT's clipboard text: a "ModPlug Tracker" header line, then one line
+// per row with "|" and an 11-character cell per channel ("|C-501v64A0F"). Unlike the pattern view,
+// instruments and volumes are decimal; lines end in CRLF as OpenMPT writes them.
+func dumpClipboard(rows []player.PatternRow, firstChannel, lastChannel int, format string) string {
+	tag, ok := clipboardFormats[format]
+	if !ok {
+		tag = clipboardFormats["it"]
+	}
+
+	var b strings.Builder
+	b.WriteString("ModPlug Tracker " + tag + "\r\n")
+	for _, row := range rows {
+		for ch := firstChannel; ch <= lastChannel; ch++ {
+			cell := player.PatternCell{}
+			if ch < len(row.Channels) {
+				cell = row.Channels[ch]
+			}
+			b.WriteString("|" + clipboardCell(cell))
+		}
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// clipboardCell formats one cell for the clipboard, taking command letters from libopenmpt's text
+// so effects stay in the module's own notation
+func clipboardCell(cell player.PatternCell) string {
+	text := cellText(cell, fullCellWidth)
+
+	note := formatNote(cell.Note)
+
+	instrument := ".."
+	if cell.Instrument > 0 {
+		instrument = fmt.Sprintf("%02d", cell.Instrument)
+	}
+
+	volume := "..."
+	if cell.VolumeEffect > 0 {
+		volume = fmt.Sprintf("%c%02d", text[player.CellVolumeAt], cell.Volume)
+	}
+
+	effect := "..."
+	if cell.Effect > 0 {
+		effect = fmt.Sprintf("%c%02X", text[player.CellEffectAt], cell.Parameter)
+	}
+
+	return note + instrument + volume + effect
+}
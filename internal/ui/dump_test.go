@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+func TestClipboardCell(t *testing.T) {
+	tests := []struct {
+		name string
+		cell player.PatternCell
+		want string
+	}{
+		{"empty", player.PatternCell{}, "..........."},
+		{
+			// Instrument and volume are decimal on the clipboard, the parameter stays hex
+			"full cell",
+			player.PatternCell{Note: 61, Instrument: 12, VolumeEffect: player.VolumeEffectVolume, Volume: 64,
+				Effect: 1, Parameter: 0x0F, Text: "C-5 0C v64 A0F"},
+			"C-512v64A0F",
+		},
+		{
+			"letters come from the text",
+			player.PatternCell{VolumeEffect: 2, Volume: 32, Effect: 4, Parameter: 0xA0, Text: "... .. p32 DA0"},
+			".....p32DA0",
+		},
+		{"note off", player.PatternCell{Note: player.NoteOff, Text: "=== .. ... ..."}, "===........"},
+		{"instrument only", player.PatternCell{Instrument: 99, Text: "... 63 ... ..."}, "...99......"},
+	}
+	for _, tt := range tests {
+		if got := clipboardCell(tt.cell); got != tt.want {
+			t.Errorf("%s: clipboardCell = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDumpClipboard(t *testing.T) {
+	rows := []player.PatternRow{
+		{Channels: []player.PatternCell{
+			{Note: 61, Instrument: 1, Text: "C-5 01 ... ..."},
+			{Effect: 6, Parameter: 0x06, Text: "... .. ... F06"},
+			{Note: 49, Text: "C-4 .. ... ..."},
+		}},
+		{Channels: []player.PatternCell{{}}}, // Short rows are padded with empty cells
+	}
+
+	tests := []struct {
+		format                    string
+		firstChannel, lastChannel int
+		want                      string
+	}{
+		{"xm", 0, 1, "ModPlug Tracker  XM\r\n" +
+			"|C-501......|........F06\r\n" +
+			"|...........|...........\r\n"},
+		{"mod", 1, 2, "ModPlug Tracker MOD\r\n" +
+			"|........F06|C-4........\r\n" +
+			"|...........|...........\r\n"},
+		{"669", 0, 0, "ModPlug Tracker  IT\r\n" +
+			"|C-501......\r\n" +
+			"|...........\r\n"},
+	}
+	for _, tt := range tests {
+		if got := dumpClipboard(rows, tt.firstChannel, tt.lastChannel, tt.format); got != tt.want {
+			t.Errorf("dumpClipboard(%s, %d-%d) =\n%q\nwant\n%q", tt.format, tt.firstChannel, tt.lastChannel, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import "math"
+
+// fft performs an in-place radix-2 Cooley-Tukey FFT on (re, im)
+// len(re) must be a power of two and equal to len(im)
+func fft(re, im []float64) {
+	n := len(re)
+	if n < 2 {
+		return
+	}
+
+	// Bit-reversal permutation
+	j := 0
+	for i := 1; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+		if i < j {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+
+	// Butterflies
+	for size := 2; size <= n; size <<= 1 {
+		angle := -2 * math.Pi / float64(size)
+		wRe, wIm := math.Cos(angle), math.Sin(angle)
+		half := size / 2
+		for start := 0; start < n; start += size {
+			uRe, uIm := 1.0, 0.0
+			for k := 0; k < half; k++ {
+				a := start + k
+				b := a + half
+				tRe := re[b]*uRe - im[b]*uIm
+				tIm := re[b]*uIm + im[b]*uRe
+				re[b] = re[a] - tRe
+				im[b] = im[a] - tIm
+				re[a] += tRe
+				im[a] += tIm
+				uRe, uIm = uRe*wRe-uIm*wIm, uRe*wIm+uIm*wRe
+			}
+		}
+	}
+}
+
+// hannWindow returns the n-point Hann window
+func hannWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+	}
+	return w
+}
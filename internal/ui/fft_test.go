@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFFTSinePeak(t *testing.T) {
+	tests := []struct {
+		n, bin int
+	}{
+		{8, 1},
+		{64, 5},
+		{1024, 37},
+		{fftSize, 300},
+	}
+	for _, tt := range tests {
+		re := make([]float64, tt.n)
+		im := make([]float64, tt.n)
+		for i := range re {
+			re[i] = math.Sin(2 * math.Pi * float64(tt.bin*i) / float64(tt.n))
+		}
+		fft(re, im)
+
+		peak, peakMag := 0, 0.0
+		for k := 0; k < tt.n/2; k++ {
+			if m := math.Hypot(re[k], im[k]); m > peakMag {
+				peak, peakMag = k, m
+			}
+		}
+		if peak != tt.bin {
+			t.Errorf("n=%d: peak at bin %d, want %d", tt.n, peak, tt.bin)
+		}
+		// A unit sine puts n/2 in its bin and (nearly) nothing elsewhere
+		if want := float64(tt.n) / 2; math.Abs(peakMag-want) > 1e-6*want {
+			t.Errorf("n=%d: peak magnitude %g, want %g", tt.n, peakMag, want)
+		}
+		for k := 0; k < tt.n/2; k++ {
+			if k != tt.bin && math.Hypot(re[k], im[k]) > 1e-6*float64(tt.n) {
+				t.Errorf("n=%d: leakage %g in bin %d", tt.n, math.Hypot(re[k], im[k]), k)
+			}
+		}
+	}
+}
+
+func TestFFTConstant(t *testing.T) {
+	re := []float64{1, 1, 1, 1, 1, 1, 1, 1}
+	im := make([]float64, len(re))
+	fft(re, im)
+	if re[0] != 8 || im[0] != 0 {
+		t.Errorf("DC bin = %g%+gi, want 8", re[0], im[0])
+	}
+	for k := 1; k < len(re); k++ {
+		if math.Hypot(re[k], im[k]) > 1e-9 {
+			t.Errorf("bin %d = %g%+gi, want 0", k, re[k], im[k])
+		}
+	}
+}
+
+func TestSpectrumSineBand(t *testing.T) {
+	binHz := outputSampleRate / fftSize
+	for _, bin := range []int{10, 46, 200} {
+		a := NewSpectrumAnalyzer(DefaultSpectrumBands)
+
+		// Full-scale sine centred on an FFT bin, so the Hann window reads it at 0 dBFS
+		left := make([]float32, fftSize)
+		for i := range left {
+			left[i] = float32(math.Sin(2 * math.Pi * float64(bin) * binHz * float64(i) / outputSampleRate))
+		}
+		a.Update(left, left)
+
+		want := -1
+		for b := 0; b < a.bands; b++ {
+			if bin >= a.edges[b] && bin < a.edges[b+1] {
+				want = b
+			}
+		}
+		if want < 0 {
+			t.Fatalf("bin %d (%.0f Hz) is outside every band", bin, float64(bin)*binHz)
+		}
+
+		loudest := 0
+		for b := range a.levels {
+			if a.levels[b] > a.levels[loudest] {
+				loudest = b
+			}
+		}
+		if loudest != want {
+			t.Errorf("%.0f Hz: loudest band %d, want %d", float64(bin)*binHz, loudest, want)
+		}
+		// One tick of attack from silence towards 0 dBFS
+		if got := a.levels[want]; math.Abs(got-spectrumAttack) > 0.01 {
+			t.Errorf("%.0f Hz: band level %.3f after one update, want %.3f", float64(bin)*binHz, got, spectrumAttack)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// harmonyRows is the height of the chord/key line
+	harmonyRows = 1
+
+	// harmonyKeyWindow is how many timeline rows on each side of a row feed its key estimate
+	harmonyKeyWindow = 64
+
+	// Chord chart layout: one line per bar of chartBeatsPerBar beats
+	chartRowsPerBeat = 4
+	chartBeatsPerBar = 4
+	chartChordWidth  = 9
+)
+
+// pitchClassNames spells pitch classes the way chord charts usually do
+var pitchClassNames = []string{"C", "C#", "D", "Eb", "E", "F", "F#", "G", "Ab", "A", "Bb", "B"}
+
+// chordTemplate is a chord quality as semitones above the root
+type chordTemplate struct {
+	suffix    string
+	intervals []int
+}
+
+// chordTemplates are tried for every sounding root; earlier entries win ties
+var chordTemplates = []chordTemplate{
+	{"", []int{0, 4, 7}},
+	{"m", []int{0, 3, 7}},
+	{"7", []int{0, 4, 7, 10}},
+	{"m7", []int{0, 3, 7, 10}},
+	{"maj7", []int{0, 4, 7, 11}},
+	{"sus4", []int{0, 5, 7}},
+	{"sus2", []int{0, 2, 7}},
+	{"dim", []int{0, 3, 6}},
+	{"aug", []int{0, 4, 8}},
+	{"6", []int{0, 4, 7, 9}},
+	{"m6", []int{0, 3, 7, 9}},
+	{"m7b5", []int{0, 3, 6, 10}},
+	{"dim7", []int{0, 3, 6, 9}},
+	{"add9", []int{0, 2, 4, 7}},
+	{"madd9", []int{0, 2, 3, 7}},
+	{"7sus4", []int{0, 5, 7, 10}},
+	{"5", []int{0, 7}},
+	{"", []int{0, 4}},  // Major third alone, fifth omitted
+	{"m", []int{0, 3}}, // Minor third alone
+}
+
+// Krumhansl-Kessler key profiles: how strongly each scale degree suggests a major or minor key
+var (
+	majorProfile = []float64{6.35, 2.23, 3.48, 2.33, 4.38, 4.09, 2.52, 5.19, 2.39, 3.66, 2.29, 2.88}
+	minorProfile = []float64{6.33, 2.68, 3.52, 5.38, 2.60, 3.53, 2.54, 4.75, 3.98, 2.69, 3.34, 3.17}
+)
+
+// Harmony is the chord on every row of the piano roll timeline and the key around it
+type Harmony struct {
+	chords  []string // Per timeline row; "" when fewer than two pitch classes sound or nothing fits
+	keys    []int    // Per timeline row: 0-11 major, 12-23 minor, -1 when the window is silent
+	songKey int      // Key of the whole song, same encoding
+}
+
+// BuildHarmony names the chord on every row and estimates the key over a sliding window
+// Chords come from the notes sounding on each channel (note-on until off/cut/fade), as in the roll
+func BuildHarmony(roll *PianoRoll) *Harmony {
+	h := &Harmony{songKey: -1}
+	if roll == nil {
+		return h
+	}
+
+	h.chords = make([]string, len(roll.slots))
+	h.keys = make([]int, len(roll.slots))
+
+	// Pitch-class weights as prefix sums, so every window is two lookups
+	prefix := make([][12]float64, len(roll.slots)+1)
+	notes := make([]int, 0, 32)
+	for row, slots := range roll.slots {
+		notes = notes[:0]
+		prefix[row+1] = prefix[row]
+		for _, slot := range slots {
+			if slot.note == 0 {
+				continue
+			}
+			notes = append(notes, int(slot.note))
+			prefix[row+1][(int(slot.note)-1)%12]++
+		}
+		h.chords[row] = detectChord(notes)
+	}
+
+	for row := range h.keys {
+		first := max(0, row-harmonyKeyWindow)
+		last := min(len(roll.slots), row+harmonyKeyWindow+1)
+		var weights [12]float64
+		for pc := range weights {
+			weights[pc] = prefix[last][pc] - prefix[first][pc]
+		}
+		h.keys[row] = estimateKey(weights)
+	}
+	h.songKey = estimateKey(prefix[len(roll.slots)])
+
+	return h
+}
+
+// At returns the chord and key names at a timeline row ("" outside the song or when silent)
+func (h *Harmony) At(row int) (string, string) {
+	if h == nil || row < 0 || row >= len(h.chords) {
+		return "", ""
+	}
+	return h.chords[row], keyName(h.keys[row])
+}
+
+// SongKey names the key estimated from the whole song
+func (h *Harmony) SongKey() string {
+	if h == nil {
+		return ""
+	}
+	return keyName(h.songKey)
+}
+
+// detectChord names the chord formed by sounding notes (libopenmpt note numbers, C-0 = 1)
+// Every sounding pitch class is tried as the root; the template covering the most notes with the
+// fewest left over wins, and the bass note breaks ties. Inversions are written as slash chords.
+func detectChord(notes []int) string {
+	var present [12]bool
+	distinct, bass := 0, 0
+	for _, note := range notes {
+		pc := (note - 1) % 12
+		if !present[pc] {
+			present[pc] = true
+			distinct++
+		}
+		if bass == 0 || note < bass {
+			bass = note
+		}
+	}
+	if distinct < 2 {
+		return ""
+	}
+	bassPC := (bass - 1) % 12
+
+	best, bestRoot, bestScore := -1, 0, math.MinInt
+	for root := 0; root < 12; root++ {
+		if !present[root] {
+			continue
+		}
+		for i, t := range chordTemplates {
+			matched := true
+			for _, interval := range t.intervals {
+				if !present[(root+interval)%12] {
+					matched = false
+					break
+				}
+			}
+			extra := distinct - len(t.intervals)
+			if !matched || extra > 2 {
+				continue
+			}
+			score := (len(t.intervals)*4-extra*3)*100 - i
+			if root == bassPC {
+				score += 200
+			}
+			if score > bestScore {
+				best, bestRoot, bestScore = i, root, score
+			}
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+
+	name := pitchClassNames[bestRoot] + chordTemplates[best].suffix
+	if bassPC != bestRoot {
+		name += "/" + pitchClassNames[bassPC]
+	}
+	return name
+}
+
+// estimateKey correlates pitch-class weights with every major and minor key profile
+func estimateKey(weights [12]float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return -1
+	}
+
+	best, bestR := -1, math.Inf(-1)
+	for tonic := 0; tonic < 12; tonic++ {
+		for mode, profile := range [][]float64{majorProfile, minorProfile} {
+			if r := correlation(weights, profile, tonic); r > bestR {
+				best, bestR = tonic+mode*12, r
+			}
+		}
+	}
+	return best
+}
+
+// correlation is the Pearson correlation of weights with a profile rotated to tonic
+func correlation(weights [12]float64, profile []float64, tonic int) float64 {
+	var meanW, meanP float64
+	for i := 0; i < 12; i++ {
+		meanW += weights[i] / 12
+		meanP += profile[i] / 12
+	}
+	var cov, varW, varP float64
+	for i := 0; i < 12; i++ {
+		dw := weights[(tonic+i)%12] - meanW
+		dp := profile[i] - meanP
+		cov += dw * dp
+		varW += dw * dw
+		varP += dp * dp
+	}
+	if varW == 0 || varP == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varW*varP)
+}
+
+// keyName spells a key index ("A minor"), "" for -1
+func keyName(key int) string {
+	switch {
+	case key < 0:
+		return ""
+	case key < 12:
+		return pitchClassNames[key] + " major"
+	}
+	return pitchClassNames[key-12] + " minor"
+}
+
+// RenderHarmony draws the chord and key line for a timeline row
+func RenderHarmony(h *Harmony, row int, width int, palette ColorPalette) string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	if h == nil {
+		// Still being built in the background
+		return renderBuilding(labelStyle.Render(fmt.Sprintf("%-4s", "Harm"))+" │ ", harmonyRows, palette)
+	}
+	chordStyle := lipgloss.NewStyle().Bold(true).Foreground(palette.Note)
+	valueStyle := lipgloss.NewStyle().Foreground(palette.InfoValue)
+
+	chord, key := h.At(row)
+	songKey := h.SongKey()
+	if chord == "" {
+		chord = "-"
+	}
+	if key == "" {
+		key = "-"
+	}
+
+	line := labelStyle.Render(fmt.Sprintf("%-4s", "Harm")) + " │ " +
+		labelStyle.Render("Chord ") + chordStyle.Render(fitWidth(chord, chartChordWidth)) +
+		labelStyle.Render("Key ") + valueStyle.Render(fitWidth(key, 10))
+	if songKey != "" {
+		line += labelStyle.Render("  Song ") + valueStyle.Render(songKey)
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
+
+// ChordChartLines lays out the song as bars of beat chords, shared with `gomod chords`
+// Each beat shows the chord that sounds longest in it, "." when it continues the previous beat,
+// and "-" for no chord. The key is printed whenever the estimate changes.
+func ChordChartLines(roll *PianoRoll, h *Harmony, orders []int) []string {
+	var lines []string
+	lines = append(lines, "Key: "+orDash(h.SongKey())+" (whole song)")
+	lines = append(lines, "")
+	header := "Ord Pat  Row │"
+	for beat := 1; beat <= chartBeatsPerBar; beat++ {
+		header += " " + fitWidth(fmt.Sprintf("Beat %d", beat), chartChordWidth)
+	}
+	lines = append(lines, header+" │ Key")
+	lines = append(lines, strings.Repeat("─", len([]rune(header))+6))
+
+	lastKey := -2
+	for order, pattern := range orders {
+		start := roll.Position(order, 0)
+		if start < 0 {
+			continue
+		}
+		end := len(roll.slots)
+		for next := order + 1; next < len(orders); next++ {
+			if s := roll.Position(next, 0); s >= 0 {
+				end = s
+				break
+			}
+		}
+
+		for bar := start; bar < end; bar += chartRowsPerBeat * chartBeatsPerBar {
+			line := fmt.Sprintf("%03d %03d %04X │", order, pattern, bar-start)
+			previous := ""
+			for beat := 0; beat < chartBeatsPerBar; beat++ {
+				first := bar + beat*chartRowsPerBeat
+				if first >= end {
+					break
+				}
+				chord := beatChord(h.chords[first:min(end, first+chartRowsPerBeat)])
+				text := orDash(chord)
+				if beat > 0 && chord == previous {
+					text = "."
+				}
+				previous = chord
+				line += " " + fitWidth(text, chartChordWidth)
+			}
+			line = fitWidth(line, len([]rune(header))) + " │"
+			if key := h.keys[bar]; key != lastKey {
+				line += " " + orDash(keyName(key))
+				lastKey = key
+			}
+			lines = append(lines, strings.TrimRight(line, " "))
+		}
+	}
+	return lines
+}
+
+// beatChord picks the chord held for the most rows of a beat (the earliest on a tie)
+func beatChord(chords []string) string {
+	best, bestCount := "", 0
+	for i, chord := range chords {
+		count := 0
+		for _, other := range chords[i:] {
+			if other == chord {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = chord, count
+		}
+	}
+	return best
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package ui
+
+import "testing"
+
+// note returns the libopenmpt note number for a pitch class (0 = C) in an octave
+func note(pc, octave int) int {
+	return octave*12 + pc + 1
+}
+
+func TestDetectChord(t *testing.T) {
+	const (
+		c, d, eb, e, f, gb, g, a, bb, b = 0, 2, 3, 4, 5, 6, 7, 9, 10, 11
+	)
+	tests := []struct {
+		name  string
+		notes []int
+		want  string
+	}{
+		{"C major", []int{note(c, 4), note(e, 4), note(g, 4)}, "C"},
+		{"A minor", []int{note(a, 3), note(c, 4), note(e, 4)}, "Am"},
+		{"order doesn't matter", []int{note(g, 4), note(c, 4), note(e, 4)}, "C"},
+		{"doubled notes", []int{note(c, 3), note(c, 4), note(e, 4), note(g, 4), note(g, 5)}, "C"},
+		{"first inversion", []int{note(e, 3), note(g, 3), note(c, 4)}, "C/E"},
+		{"second inversion", []int{note(g, 3), note(c, 4), note(e, 4)}, "C/G"},
+		{"dominant seventh", []int{note(g, 3), note(b, 3), note(d, 4), note(f, 4)}, "G7"},
+		{"major seventh", []int{note(c, 4), note(e, 4), note(g, 4), note(b, 4)}, "Cmaj7"},
+		{"diminished", []int{note(c, 4), note(eb, 4), note(gb, 4)}, "Cdim"},
+		{"power chord", []int{note(c, 3), note(g, 3)}, "C5"},
+		{"third alone", []int{note(c, 4), note(e, 4)}, "C"},
+		{"sus2 over its root", []int{note(c, 4), note(d, 4), note(g, 4)}, "Csus2"},
+		// Same pitch classes, different bass: Dm7 and F6 share D F A C
+		{"Dm7", []int{note(d, 3), note(f, 3), note(a, 3), note(c, 4)}, "Dm7"},
+		{"F6", []int{note(f, 3), note(a, 3), note(c, 4), note(d, 4)}, "F6"},
+		{"seventh in the bass", []int{note(bb, 2), note(c, 3), note(e, 3), note(g, 3)}, "C7/Bb"},
+		{"single note", []int{note(c, 4)}, ""},
+		{"octave", []int{note(c, 4), note(c, 5)}, ""},
+		{"cluster", []int{note(c, 4), note(c, 4) + 1, note(d, 4)}, ""},
+		{"silence", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := detectChord(tt.notes); got != tt.want {
+			t.Errorf("%s: detectChord = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateKey(t *testing.T) {
+	// rotate places a profile's tonic on pitch class tonic
+	rotate := func(profile []float64, tonic int) [12]float64 {
+		var weights [12]float64
+		for i, w := range profile {
+			weights[(tonic+i)%12] = w
+		}
+		return weights
+	}
+	// scale weights the tonic triad twice as much as the other scale degrees
+	scale := func(tonic int, degrees, triad []int) [12]float64 {
+		var weights [12]float64
+		for _, d := range degrees {
+			weights[(tonic+d)%12]++
+		}
+		for _, d := range triad {
+			weights[(tonic+d)%12]++
+		}
+		return weights
+	}
+	majorScale, majorTriad := []int{0, 2, 4, 5, 7, 9, 11}, []int{0, 4, 7}
+	minorScale, minorTriad := []int{0, 2, 3, 5, 7, 8, 10}, []int{0, 3, 7}
+
+	tests := []struct {
+		name    string
+		weights [12]float64
+		want    string
+	}{
+		{"major profile on C", rotate(majorProfile, 0), "C major"},
+		{"major profile on G", rotate(majorProfile, 7), "G major"},
+		{"minor profile on A", rotate(minorProfile, 9), "A minor"},
+		{"minor profile on F#", rotate(minorProfile, 6), "F# minor"},
+		{"D major scale", scale(2, majorScale, majorTriad), "D major"},
+		{"Eb major scale", scale(3, majorScale, majorTriad), "Eb major"},
+		{"E minor scale", scale(4, minorScale, minorTriad), "E minor"},
+		{"C minor scale", scale(0, minorScale, minorTriad), "C minor"},
+		{"silence", [12]float64{}, ""},
+	}
+	for _, tt := range tests {
+		if got := keyName(estimateKey(tt.weights)); got != tt.want {
+			t.Errorf("%s: estimateKey = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBeatChord(t *testing.T) {
+	tests := []struct {
+		chords []string
+		want   string
+	}{
+		{[]string{"C", "C", "C", "C"}, "C"},
+		{[]string{"C", "G", "G", "G"}, "G"},
+		{[]string{"C", "C", "G", "G"}, "C"}, // Ties go to the earliest
+		{[]string{"G", "C", "G", "C"}, "G"},
+		{[]string{"", "Am", "Am", "F"}, "Am"},
+		{[]string{"", "", "C", ""}, ""},
+		{[]string{"Dm7"}, "Dm7"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := beatChord(tt.chords); got != tt.want {
+			t.Errorf("beatChord(%q) = %q, want %q", tt.chords, got, tt.want)
+		}
+	}
+}
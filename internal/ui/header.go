@@ -9,7 +9,9 @@ import (
 )
 
 // RenderHeader creates the metadata header display
-func RenderHeader(metadata player.Metadata, filename string, currentTime float64, stereoSep int, palette ColorPalette) string {
+// The title line ends with a badge for the transport state; stop summarises the sleep timer,
+// stop-after-song and loop limits ("" hides it).
+func RenderHeader(metadata player.Metadata, filename string, currentTime float64, stereoSep int, state player.TransportState, stop string, palette ColorPalette) string {
 	// Create styles with palette
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -56,6 +58,11 @@ func RenderHeader(metadata player.Metadata, filename string, currentTime float64
 		valueStyle.Render(fmt.Sprintf("%d%%", stereoSep)),
 	)
 
+	// Sleep timer, stop-after-song, loop count
+	if stop != "" {
+		infoParts = append(infoParts, infoStyle.Render("Stop:"), valueStyle.Render(stop))
+	}
+
 	// Join with spacing
 	infoLine := ""
 	for i, part := range infoParts {
@@ -68,13 +75,51 @@ func RenderHeader(metadata player.Metadata, filename string, currentTime float64
 	}
 
 	lines := []string{
-		headerStyle.Render(fmt.Sprintf("♪ %s", title)),
+		headerStyle.Render(fmt.Sprintf("♪ %s", title)) + "  " + renderTransportBadge(state, palette),
 		infoLine,
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
+// renderTransportBadge shows the transport state; anything but playing is highlighted
+func renderTransportBadge(state player.TransportState, palette ColorPalette) string {
+	style := lipgloss.NewStyle().Foreground(palette.InfoValue)
+	if state != player.TransportPlaying {
+		style = lipgloss.NewStyle().Bold(true).Foreground(palette.Title).Background(palette.Border)
+	}
+	return style.Render(fmt.Sprintf(" %s %s ", state.Symbol(), state))
+}
+
+// RenderWarnings renders a one-line summary of libopenmpt's load warnings
+// Returns "" when the module loaded cleanly
+func RenderWarnings(warnings []string, width int, palette ColorPalette) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel).Bold(true)
+	valueStyle := lipgloss.NewStyle().Foreground(palette.InfoValue)
+
+	label := fmt.Sprintf("⚠ %d warning", len(warnings))
+	if len(warnings) > 1 {
+		label += "s"
+	}
+	label += ":"
+
+	// Show the first warning, truncated to fit the terminal
+	msg := warnings[0]
+	maxLen := width - lipgloss.Width(label) - 1
+	if maxLen < 10 {
+		maxLen = 10
+	}
+	if len([]rune(msg)) > maxLen {
+		msg = string([]rune(msg)[:maxLen-3]) + "..."
+	}
+
+	return labelStyle.Render(label) + " " + valueStyle.Render(msg)
+}
+
 func formatTime(seconds float64) string {
 	d := time.Duration(seconds * float64(time.Second))
 	minutes := int(d.Minutes())
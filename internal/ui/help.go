@@ -0,0 +1,66 @@
+package ui
+
+// helpKeyWidth is the width of the key column in the help panel
+const helpKeyWidth = 18
+
+// helpSections is the full key map shown by the ? panel; the controls bar only lists the essentials
+var helpSections = []struct {
+	title string
+	keys  [][2]string
+}{
+	{"Playback", [][2]string{
+		{"space", "Play/pause (replays the song once it has ended)"},
+		{"↑ ↓", "While paused: step one row back/forward and hear it"},
+		{"Shift+↑ ↓", "While paused: step one tick"},
+		{"z", "Cycle the sleep timer (15/30/45/60/90 min, off)"},
+		{"e", "Stop at the end or loop point of the song"},
+		{"[ ]", "Stereo separation"},
+		{"tab", "File browser"},
+		{"q", "Quit"},
+	}},
+	{"Channels", [][2]string{
+		{"← →", "Move the channel cursor"},
+		{"x / s", "Mute / solo the focused channel"},
+		{"{ }", "Focused channel volume"},
+		{"< >", "Focused channel panning"},
+		{"1-9 0 - =", "Mute the 1st-12th visible channel"},
+		{"Shift+1-9 0 - =", "Solo the 1st-12th visible channel"},
+		{"d", "Cycle pattern density"},
+	}},
+	{"Song", [][2]string{
+		{"o", "Order list"},
+		{", .", "Move the order selection (patterns with follow off)"},
+		{"enter", "Jump to the selection (or the cursor row)"},
+		{"f", "Follow on/off; with follow off ↑ ↓ PgUp PgDn browse"},
+		{"/", "Find in pattern data"},
+		{"M", "Song minimap (click to seek)"},
+		{"Shift+← →", "Move the selection one minimap column"},
+	}},
+	{"Views", [][2]string{
+		{"u", "VU meter mode"},
+		{"v", "Visualizer"},
+		{"r", "Tracker / piano roll"},
+		{"c", "Piano roll colors"},
+		{"p", "Piano keyboard"},
+		{"h", "Chord and key"},
+		{"m", "Song message"},
+		{"n", "Instrument and sample names"},
+		{"S", "Song statistics"},
+		{"?", "This help"},
+	}},
+}
+
+// helpLines lays out the key map for the text panel
+func helpLines() []string {
+	var lines []string
+	for i, section := range helpSections {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "── "+section.title+" ──")
+		for _, key := range section.keys {
+			lines = append(lines, fitWidth(key[0], helpKeyWidth)+" "+key[1])
+		}
+	}
+	return lines
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TextPanel selects which text overlay (if any) replaces the pattern area
+type TextPanel int
+
+const (
+	PanelNone TextPanel = iota
+	PanelMessage
+	PanelSampleNames
+	PanelStats
+	PanelHelp
+)
+
+// messageLines picks the song message to show
+// message_raw is the real song message; "message" falls back to libopenmpt's name listing
+func messageLines(message, messageRaw string) []string {
+	if strings.TrimSpace(messageRaw) != "" {
+		return textLines(messageRaw)
+	}
+	return textLines(message)
+}
+
+// sampleNameLines lays out instrument and sample names as one text block, one name per line
+// Blank names are kept since scroller texts use them as spacing
+func sampleNameLines(instrumentNames, sampleNames []string) []string {
+	var lines []string
+	if len(instrumentNames) > 0 {
+		lines = append(lines, "── Instruments ──")
+		for _, name := range instrumentNames {
+			lines = append(lines, cleanLine(name))
+		}
+	}
+	if len(sampleNames) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "── Samples ──")
+		for _, name := range sampleNames {
+			lines = append(lines, cleanLine(name))
+		}
+	}
+	return lines
+}
+
+// textLines normalises tracker text (CR line endings, tabs, control chars) into display lines
+func textLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.TrimRight(text, "\n ")
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = cleanLine(line)
+	}
+	return lines
+}
+
+// cleanLine expands tabs and drops control characters that would corrupt the terminal
+func cleanLine(line string) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, strings.TrimRight(line, " "))
+}
+
+// clampScroll keeps a scroll offset inside [0, total-visible]
+func clampScroll(scroll, total, visible int) int {
+	maxScroll := total - visible
+	if scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	return scroll
+}
+
+// RenderTextPanel renders a bordered, scrollable block of text of the given outer size
+func RenderTextPanel(title string, lines []string, scroll int, autoScroll bool, width, height int, palette ColorPalette) string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Border).
+		Padding(0, 1)
+
+	titleStyle := lipgloss.NewStyle().Foreground(palette.Title).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(palette.InfoValue)
+	infoStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+
+	// Border (2) + padding (2) horizontally, border (2) + title (1) + status (1) vertically
+	innerWidth := width - 4
+	if innerWidth < 10 {
+		innerWidth = 10
+	}
+	visible := height - 4
+	if visible < 1 {
+		visible = 1
+	}
+
+	scroll = clampScroll(scroll, len(lines), visible)
+
+	var body []string
+	body = append(body, titleStyle.Render(title))
+	if len(lines) == 0 {
+		body = append(body, infoStyle.Render("(empty)"))
+	}
+	for i := scroll; i < len(lines) && i < scroll+visible; i++ {
+		line := []rune(lines[i])
+		if len(line) > innerWidth {
+			line = line[:innerWidth]
+		}
+		body = append(body, textStyle.Render(string(line)))
+	}
+	// Pad so the box keeps a stable height while scrolling
+	for len(body) < visible+1 {
+		body = append(body, "")
+	}
+
+	status := fmt.Sprintf("%d-%d of %d", min(scroll+1, len(lines)), min(scroll+visible, len(lines)), len(lines))
+	if autoScroll {
+		status += "  [auto-scroll]"
+	}
+	body = append(body, infoStyle.Render(status))
+
+	return boxStyle.Width(innerWidth + 2).Render(strings.Join(body, "\n"))
+}
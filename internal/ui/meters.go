@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MeterMode selects how the channel VU meters are drawn
+type MeterMode int
+
+const (
+	MeterClassic MeterMode = iota // Mono bars on a perceptual curve (the original look)
+	MeterStereo                   // Left/right split bars per channel
+	MeterDB                       // Left/right bars on a dB scale with tick labels
+	meterModeCount
+)
+
+// next cycles through the meter modes
+func (m MeterMode) next() MeterMode {
+	return (m + 1) % meterModeCount
+}
+
+const (
+	// DefaultVUDecayMs is the release time constant; ~200ms matches the old 0.92-per-frame decay
+	DefaultVUDecayMs = 200
+	// DefaultVUPeakHoldMs is how long peak markers stay before falling
+	DefaultVUPeakHoldMs = 1000
+
+	// clipHold keeps the clip indicator lit long enough to be noticed
+	clipHold = 2 * time.Second
+	// clipLevel is the sample magnitude treated as clipping (full scale int16)
+	clipLevel = 32767.0 / 32768.0
+
+	// dbFloor is the bottom of the dB meter scale
+	dbFloor = -48.0
+	// dbMeterRows is the height of the dB meter (one row per 12 dB)
+	dbMeterRows = 4
+	// vuRows is the height of the classic and stereo meters
+	vuRows = 3
+	// masterWindow is how many output frames the master meter measures (~23ms)
+	masterWindow = 1024
+)
+
+// meterBallistics turns raw meter readings into displayed levels with release and peak hold
+type meterBallistics struct {
+	decay  time.Duration
+	hold   time.Duration
+	levels []float64
+	peaks  []float64
+	peakAt []time.Time
+	last   time.Time
+}
+
+func newMeterBallistics(decay, hold time.Duration) *meterBallistics {
+	return &meterBallistics{decay: decay, hold: hold}
+}
+
+// update feeds a new reading per meter; rises are instant, falls decay exponentially
+// A nil reading (paused, seeking, a drained step) reads as silence, so the meters fall and
+// their peaks hold instead of vanishing.
+func (b *meterBallistics) update(raw []float64, now time.Time) {
+	if raw == nil {
+		raw = make([]float64, len(b.levels))
+	}
+	if len(b.levels) != len(raw) {
+		b.levels = make([]float64, len(raw))
+		b.peaks = make([]float64, len(raw))
+		b.peakAt = make([]time.Time, len(raw))
+	}
+
+	factor := 0.0
+	if !b.last.IsZero() && b.decay > 0 {
+		factor = math.Exp(-float64(now.Sub(b.last)) / float64(b.decay))
+	}
+	b.last = now
+
+	for i, v := range raw {
+		v = math.Max(0, math.Min(1, v))
+		if v >= b.levels[i] {
+			b.levels[i] = v
+		} else {
+			b.levels[i] *= factor
+			if b.levels[i] < 0.001 {
+				b.levels[i] = 0
+			}
+		}
+
+		// Peaks latch, then fall with the same release once the hold expires
+		if b.levels[i] >= b.peaks[i] {
+			b.peaks[i] = b.levels[i]
+			b.peakAt[i] = now
+		} else if now.Sub(b.peakAt[i]) > b.hold {
+			b.peaks[i] = math.Max(b.levels[i], b.peaks[i]*factor)
+		}
+	}
+}
+
+// masterMeter tracks the output peak per side plus a latched clip indicator
+type masterMeter struct {
+	ballistics *meterBallistics
+	clipAt     time.Time
+}
+
+func newMasterMeter(decay, hold time.Duration) *masterMeter {
+	return &masterMeter{ballistics: newMeterBallistics(decay, hold)}
+}
+
+// update measures the sample peak of the latest output window
+func (m *masterMeter) update(left, right []float32, now time.Time) {
+	var peakL, peakR float64
+	for i := range left {
+		peakL = math.Max(peakL, math.Abs(float64(left[i])))
+	}
+	for i := range right {
+		peakR = math.Max(peakR, math.Abs(float64(right[i])))
+	}
+	if peakL >= clipLevel || peakR >= clipLevel {
+		m.clipAt = now
+	}
+	m.ballistics.update([]float64{peakL, peakR}, now)
+}
+
+func (m *masterMeter) clipping(now time.Time) bool {
+	return !m.clipAt.IsZero() && now.Sub(m.clipAt) < clipHold
+}
+
+// toDBLevel maps a linear amplitude onto 0..1 of the dB meter scale
+func toDBLevel(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	db := 20 * math.Log10(v)
+	return math.Max(0, math.Min(1, (db-dbFloor)/-dbFloor))
+}
+
+// toPerceptualLevel is the classic meter curve, shared by the stereo mode
+func toPerceptualLevel(v float64) float64 {
+	v = math.Max(0, math.Min(1, v))
+	perceived := v * v
+	if perceived < 0.1 {
+		perceived = perceived * 1.5
+	}
+	return perceived
+}
+
+// meterCell returns the block character for one row of a vertical bar
+// fill is how much of this row is covered (<=0 empty, >=1 full)
+func meterCell(fill float64) string {
+	eighths := []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	if fill >= 1 {
+		return "█"
+	}
+	if fill <= 0 {
+		return " "
+	}
+	return eighths[int(fill*8)]
+}
+
+// RenderStereoMeters draws left/right bars per channel, with peak markers
+// levels and peaks are already scaled to 0..1 (perceptual or dB); rowLabels sets the height
+// cellWidth matches the pattern cells so each pair sits under its channel
+func RenderStereoMeters(left, right, leftPeaks, rightPeaks []float64, rowLabels []string, mutedChannels []bool, cellWidth int, palette ColorPalette) string {
+	numChannels := len(left)
+	if numChannels == 0 {
+		return ""
+	}
+
+	meterStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	highStyle := lipgloss.NewStyle().Foreground(palette.InfoValue).Bold(true)
+	topStyle := lipgloss.NewStyle().Foreground(palette.CurrentRow).Bold(true)
+	peakStyle := lipgloss.NewStyle().Foreground(palette.Title)
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")) // Dimmed gray
+
+	rows := len(rowLabels)
+	lines := make([]strings.Builder, rows)
+
+	for row := 0; row < rows; row++ {
+		lines[row].WriteString(labelStyle.Render(fmt.Sprintf("%-4s", rowLabels[row])))
+		lines[row].WriteString(" │")
+
+		rowFromBottom := rows - 1 - row
+		style := meterStyle
+		if rowFromBottom == rows-1 {
+			style = topStyle
+		} else if rowFromBottom >= rows/2 {
+			style = highStyle
+		}
+
+		for ch := 0; ch < numChannels; ch++ {
+			isMuted := ch < len(mutedChannels) && mutedChannels[ch]
+
+			bar := func(level, peak float64) string {
+				fill := level*float64(rows) - float64(rowFromBottom)
+				cell, cellStyle := meterCell(fill), style
+				peakFill := peak*float64(rows) - float64(rowFromBottom)
+				if cell == " " && peakFill > 0 && peakFill <= 1 && peak > level+0.01 {
+					cell, cellStyle = "▔", peakStyle
+				}
+				if isMuted {
+					cellStyle = mutedStyle
+				}
+				return cellStyle.Render(cell)
+			}
+
+			// Column width matches the pattern cells, pair centred ("     L R      " at full width)
+			padLeft := max(0, (cellWidth-3)/2)
+			lines[row].WriteString(strings.Repeat(" ", padLeft))
+			lines[row].WriteString(bar(left[ch], valueAt(leftPeaks, ch)))
+			lines[row].WriteString(" ")
+			lines[row].WriteString(bar(valueAt(right, ch), valueAt(rightPeaks, ch)))
+			lines[row].WriteString(strings.Repeat(" ", max(0, cellWidth-3-padLeft)))
+			lines[row].WriteString(" │")
+		}
+	}
+
+	out := make([]string, rows)
+	for i := range lines {
+		out[i] = lines[i].String()
+	}
+	return strings.Join(out, "\n")
+}
+
+// RenderMasterMeter draws horizontal L/R output bars on the dB scale with a clip light
+func RenderMasterMeter(levels, peaks []float64, clipping bool, width int, palette ColorPalette) string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	barStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	peakStyle := lipgloss.NewStyle().Foreground(palette.Title)
+	valueStyle := lipgloss.NewStyle().Foreground(palette.InfoValue)
+	clipStyle := lipgloss.NewStyle().Bold(true).Foreground(palette.CurrentRow).Background(palette.CurrentRowBg)
+	idleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	// "OUT  │" + 2 × ("L " + bar + " -xx.x dB  ") + "CLIP"
+	barWidth := (width - 6 - 2*13 - 4) / 2
+	if barWidth > 40 {
+		barWidth = 40
+	}
+	if barWidth < 8 {
+		barWidth = 8
+	}
+
+	side := func(name string, level, peak float64) string {
+		filled := int(toDBLevel(level) * float64(barWidth))
+		peakPos := int(toDBLevel(peak) * float64(barWidth))
+		var b strings.Builder
+		for i := 0; i < barWidth; i++ {
+			switch {
+			case i < filled:
+				b.WriteString(barStyle.Render("█"))
+			case i == peakPos && peakPos > 0:
+				b.WriteString(peakStyle.Render("│"))
+			default:
+				b.WriteString(idleStyle.Render("·"))
+			}
+		}
+		db := "  -inf"
+		if level > 0 {
+			db = fmt.Sprintf("%6.1f", 20*math.Log10(level))
+		}
+		return labelStyle.Render(name+" ") + b.String() + valueStyle.Render(db+" dB  ")
+	}
+
+	clip := idleStyle.Render("CLIP")
+	if clipping {
+		clip = clipStyle.Render("CLIP")
+	}
+
+	return labelStyle.Render("OUT ") + " │" +
+		side("L", valueAt(levels, 0), valueAt(peaks, 0)) +
+		side("R", valueAt(levels, 1), valueAt(peaks, 1)) +
+		clip
+}
+
+// dbRowLabels labels each row of the dB meter with the level at its top edge
+func dbRowLabels() []string {
+	labels := make([]string, dbMeterRows)
+	for row := range labels {
+		labels[row] = fmt.Sprintf("%d", int(dbFloor*float64(row)/dbMeterRows))
+	}
+	return labels
+}
+
+func valueAt(values []float64, i int) float64 {
+	if i < 0 || i >= len(values) {
+		return 0
+	}
+	return values[i]
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minimapMaxRows is the most channels the minimap shows at once (one row each)
+// Wider modules show a window that follows the channel cursor, like the pattern view.
+const minimapMaxRows = 8
+
+// minimapShades go from an empty channel to one with a note on every row
+var minimapShades = []string{"·", "░", "▒", "▓", "█"}
+
+// Minimap is a whole-song overview: note density per order, per channel
+type Minimap struct {
+	density  [][]float64 // density[order][channel] in 0..1, nil for +++ and --- markers
+	channels int
+	rows     int
+}
+
+// BuildMinimap measures note density for every order from the pattern cache
+func BuildMinimap(mod *player.Module, orders []int, numChannels int) *Minimap {
+	mm := &Minimap{
+		density:  make([][]float64, len(orders)),
+		channels: numChannels,
+		rows:     max(1, min(minimapMaxRows, numChannels)),
+	}
+
+	for order, pattern := range orders {
+		if pattern == player.OrderSkip || pattern == player.OrderEnd {
+			continue
+		}
+		notes := make([]int, numChannels)
+		rows := mod.GetCachedPattern(pattern).Rows
+		for _, row := range rows {
+			for ch, cell := range row.Channels {
+				if ch < numChannels && cell.Note >= player.NoteMin && cell.Note <= player.NoteMax {
+					notes[ch]++
+				}
+			}
+		}
+		mm.density[order] = make([]float64, numChannels)
+		if len(rows) > 0 {
+			for ch := range notes {
+				mm.density[order][ch] = float64(notes[ch]) / float64(len(rows))
+			}
+		}
+	}
+	return mm
+}
+
+// firstChannel picks the channels shown: the pattern view's, scrolled to keep the focus visible
+func (mm *Minimap) firstChannel(w ChannelWindow) int {
+	return scrollChannelWindow(w.First, w.Focus, mm.rows, mm.channels)
+}
+
+// Rows returns the height of the rendered minimap (one line while it is being built)
+func (mm *Minimap) Rows() int {
+	if mm == nil {
+		return 1
+	}
+	return mm.rows
+}
+
+// columns returns how many columns the map uses at this width (one per order when they fit)
+func (mm *Minimap) columns(width int) int {
+	return max(1, min(len(mm.density), width-6))
+}
+
+// orderRange returns the orders [first, last) drawn in column col
+func (mm *Minimap) orderRange(col, cols int) (int, int) {
+	first := col * len(mm.density) / cols
+	last := (col + 1) * len(mm.density) / cols
+	return first, max(last, first+1)
+}
+
+// OrderAt maps a column of the rendered map (after the label) to the first order it shows
+// Returns -1 outside the map
+func (mm *Minimap) OrderAt(col, width int) int {
+	if mm == nil || len(mm.density) == 0 {
+		return -1
+	}
+	cols := mm.columns(width)
+	if col < 0 || col >= cols {
+		return -1
+	}
+	first, _ := mm.orderRange(col, cols)
+	return first
+}
+
+// ColumnStep returns how many orders one map column covers, for keyboard jumps
+func (mm *Minimap) ColumnStep(width int) int {
+	if mm == nil || len(mm.density) == 0 {
+		return 1
+	}
+	return max(1, len(mm.density)/mm.columns(width))
+}
+
+// RenderMinimap draws the overview with the playhead and the order cursor highlighted
+// Orders are merged (densest wins) when there are more orders than columns. Each row is a
+// channel, labelled with its number; the focused channel's label is highlighted.
+func RenderMinimap(mm *Minimap, window ChannelWindow, currentOrder, cursor int, width int, palette ColorPalette) string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	if mm == nil {
+		// Still being built in the background
+		return renderBuilding(labelStyle.Render("Map ")+" │", 1, palette)
+	}
+	if len(mm.density) == 0 {
+		return ""
+	}
+
+	focusStyle := lipgloss.NewStyle().Foreground(palette.Title).Bold(true)
+	mapStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	playStyle := lipgloss.NewStyle().Foreground(palette.CurrentRow).Background(palette.CurrentRowBg)
+	cursorStyle := lipgloss.NewStyle().Foreground(palette.Title).Background(palette.Border)
+
+	cols := mm.columns(width)
+
+	firstChannel := mm.firstChannel(window)
+
+	var lines []string
+	for ch := firstChannel; ch < min(firstChannel+mm.rows, mm.channels); ch++ {
+		var b strings.Builder
+		label := fmt.Sprintf("Ch%02d", ch+1)
+		if ch == window.Focus {
+			b.WriteString(focusStyle.Render(label))
+		} else {
+			b.WriteString(labelStyle.Render(label))
+		}
+		b.WriteString(" │")
+
+		// Plain columns are batched into one Render per run
+		var run strings.Builder
+		flush := func() {
+			if run.Len() > 0 {
+				b.WriteString(mapStyle.Render(run.String()))
+				run.Reset()
+			}
+		}
+
+		for col := 0; col < cols; col++ {
+			first, last := mm.orderRange(col, cols)
+			cell := " "
+			density, playable := 0.0, false
+			for order := first; order < last && order < len(mm.density); order++ {
+				if mm.density[order] != nil {
+					playable = true
+					density = math.Max(density, mm.density[order][ch])
+				}
+			}
+			if playable {
+				// Square root keeps sparse orders visible; any note at all gets at least ░
+				shade := int(math.Ceil(math.Sqrt(density) * float64(len(minimapShades)-1)))
+				cell = minimapShades[min(shade, len(minimapShades)-1)]
+			}
+
+			switch {
+			case currentOrder >= first && currentOrder < last:
+				flush()
+				b.WriteString(playStyle.Render(cell))
+			case cursor >= first && cursor < last:
+				flush()
+				b.WriteString(cursorStyle.Render(cell))
+			default:
+				run.WriteString(cell)
+			}
+		}
+		flush()
+		lines = append(lines, b.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
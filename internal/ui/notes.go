@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyboardRows is the height of the piano keyboard panel
+const keyboardRows = 2
+
+// noteTracker follows which note is sounding on each channel as playback moves through rows
+// A note sounds from its note-on until a note-off, cut or fade on the same channel
+type noteTracker struct {
+	notes   []int // Sounding note per channel (0 = silent)
+	order   int
+	pattern int
+	row     int
+	valid   bool
+}
+
+// reset silences every channel and forgets the position
+func (t *noteTracker) reset(numChannels int) {
+	t.notes = make([]int, numChannels)
+	t.valid = false
+}
+
+// apply processes one pattern row
+func (t *noteTracker) apply(row player.PatternRow) {
+	for ch, cell := range row.Channels {
+		if ch >= len(t.notes) {
+			break
+		}
+		switch {
+		case cell.Note >= player.NoteMin && cell.Note <= player.NoteMax:
+			t.notes[ch] = cell.Note
+		case cell.Note == player.NoteOff, cell.Note == player.NoteCut, cell.Note == player.NoteFade:
+			t.notes[ch] = 0
+		}
+	}
+}
+
+// applyRows processes rows[from..to] of a pattern
+func (t *noteTracker) applyRows(rows []player.PatternRow, from, to int) {
+	for r := from; r <= to && r < len(rows); r++ {
+		t.apply(rows[r])
+	}
+}
+
+// restore copies the sounding notes from a piano roll timeline row, reporting whether it could
+func (t *noteTracker) restore(roll *PianoRoll, order, row, patternRows int) bool {
+	at := roll.Position(order, row)
+	if at < 0 || row >= patternRows || at >= len(roll.slots) {
+		return false
+	}
+	for ch := range t.notes {
+		t.notes[ch] = 0
+		if ch < len(roll.slots[at]) {
+			t.notes[ch] = int(roll.slots[at][ch].note)
+		}
+	}
+	return true
+}
+
+// update advances the tracker to (order, pattern, row)
+// Consecutive rows are applied in sequence, including the end of the previous pattern, so rows
+// skipped between UI ticks still count. After a jump or seek the state comes from the piano roll,
+// which holds the sounding notes on every timeline row; the current pattern is replayed from its
+// top only when the position is not on the roll (e.g. while it is still being built).
+func (t *noteTracker) update(mod *player.Module, roll *PianoRoll, numChannels, order, pattern, row int) {
+	if len(t.notes) != numChannels {
+		t.reset(numChannels)
+	}
+	if t.valid && order == t.order && row == t.row {
+		return
+	}
+
+	rows := mod.GetCachedPattern(pattern).Rows
+	switch {
+	case t.valid && order == t.order && row > t.row:
+		t.applyRows(rows, t.row+1, row)
+	case t.valid && order == t.order+1:
+		// Natural progression into the next order: finish the previous pattern first
+		prev := mod.GetCachedPattern(t.pattern).Rows
+		t.applyRows(prev, t.row+1, len(prev)-1)
+		t.applyRows(rows, 0, row)
+	default:
+		if !t.restore(roll, order, row, len(rows)) {
+			t.reset(numChannels)
+			t.applyRows(rows, 0, row)
+		}
+	}
+
+	t.order, t.pattern, t.row, t.valid = order, pattern, row, true
+}
+
+// isBlackKey reports whether a note (1-based, C-0 = 1) is a sharp
+func isBlackKey(note int) bool {
+	switch (note - 1) % 12 {
+	case 1, 3, 6, 8, 10:
+		return true
+	}
+	return false
+}
+
+// RenderPianoKeyboard draws a horizontal keyboard with sounding notes lit in their channel's color
+// One column per semitone; the visible octaves adapt to the terminal width
+func RenderPianoKeyboard(notes []int, mutedChannels []bool, width int, palette ColorPalette) string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	whiteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	blackStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	octaveStyle := lipgloss.NewStyle().Foreground(palette.RowNumber)
+
+	// Same label column as the VU meters ("VU▲ │")
+	octaves := (width - 6) / 12
+	if octaves > 10 {
+		octaves = 10
+	}
+	if octaves < 1 {
+		octaves = 1
+	}
+	// Centre the window on C-5 (middle C in tracker numbering)
+	startOctave := 5 - octaves/2
+	if startOctave+octaves > 10 {
+		startOctave = 10 - octaves
+	}
+	if startOctave < 0 {
+		startOctave = 0
+	}
+
+	// Lowest unmuted channel wins when several play the same note
+	active := make(map[int]int)
+	for ch := len(notes) - 1; ch >= 0; ch-- {
+		if notes[ch] == 0 || (ch < len(mutedChannels) && mutedChannels[ch]) {
+			continue
+		}
+		active[notes[ch]] = ch
+	}
+
+	var top, bottom strings.Builder
+	top.WriteString(labelStyle.Render(fmt.Sprintf("%-4s", "Keys")))
+	top.WriteString(" │")
+	bottom.WriteString(labelStyle.Render("    "))
+	bottom.WriteString(" │")
+
+	first := startOctave*12 + 1
+	last := first + octaves*12
+	for note := first; note < last; note++ {
+		ch, lit := active[note]
+		litStyle := lipgloss.NewStyle().Foreground(ChannelColor(ch))
+
+		if isBlackKey(note) {
+			if lit {
+				top.WriteString(litStyle.Render("█"))
+			} else {
+				top.WriteString(blackStyle.Render("█"))
+			}
+			// Black keys are short: the white keys continue underneath
+			bottom.WriteString(whiteStyle.Render("▀"))
+			continue
+		}
+
+		if lit {
+			top.WriteString(litStyle.Render("█"))
+			bottom.WriteString(litStyle.Render("▀"))
+		} else if (note-1)%12 == 0 {
+			// Label each C with its octave
+			top.WriteString(whiteStyle.Render("█"))
+			bottom.WriteString(octaveStyle.Render(fmt.Sprintf("%d", (note-1)/12)))
+		} else {
+			top.WriteString(whiteStyle.Render("█"))
+			bottom.WriteString(whiteStyle.Render("▀"))
+		}
+	}
+
+	return top.String() + "\n" + bottom.String()
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// orderListWidth is the rendered width of the order panel (including its left border)
+const orderListWidth = 12
+
+// RenderOrderList renders the song order as a vertical list of the given height
+// currentOrder is highlighted like the pattern view's current row; cursor marks the selection
+func RenderOrderList(orders []int, currentOrder, cursor int, height int, palette ColorPalette) string {
+	if height < 3 {
+		height = 3
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	orderStyle := lipgloss.NewStyle().Foreground(palette.RowNumber)
+	patternStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	markerStyle := lipgloss.NewStyle().Foreground(palette.Effect)
+	currentStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(palette.CurrentRow).
+		Background(palette.CurrentRowBg)
+	cursorStyle := lipgloss.NewStyle().Foreground(palette.Title).Bold(true)
+
+	inner := orderListWidth - 2
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("%-*s", inner, "Order")))
+	lines = append(lines, strings.Repeat("─", inner))
+
+	// Keep the cursor roughly centered, like the typewriter pattern view
+	visible := height - 2
+	start := cursor - visible/2
+	if start > len(orders)-visible {
+		start = len(orders) - visible
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	for i := start; i < start+visible; i++ {
+		if i >= len(orders) {
+			lines = append(lines, strings.Repeat(" ", inner))
+			continue
+		}
+
+		var patStr string
+		switch orders[i] {
+		case player.OrderSkip:
+			patStr = "+++"
+		case player.OrderEnd:
+			patStr = "---"
+		default:
+			patStr = fmt.Sprintf("%03d", orders[i])
+		}
+
+		marker := " "
+		if i == cursor {
+			marker = "▶"
+		}
+
+		text := fmt.Sprintf("%03d %s", i, patStr)
+		var line string
+		if i == currentOrder {
+			line = currentStyle.Render(text)
+		} else if orders[i] == player.OrderSkip || orders[i] == player.OrderEnd {
+			line = orderStyle.Render(fmt.Sprintf("%03d ", i)) + markerStyle.Render(patStr)
+		} else {
+			line = orderStyle.Render(fmt.Sprintf("%03d ", i)) + patternStyle.Render(patStr)
+		}
+		lines = append(lines, cursorStyle.Render(marker)+line+strings.Repeat(" ", inner-1-len(text)))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(palette.Border).
+		PaddingLeft(1).
+		Render(strings.Join(lines, "\n"))
+}
@@ -8,25 +8,31 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Pattern command types
+// Pattern command types (libopenmpt's OPENMPT_MODULE_COMMAND_* indices)
 const (
-	CommandNote        = 0
-	CommandInstrument  = 1
-	CommandVolume      = 2
-	CommandEffect      = 3
-	CommandEffectParam = 4
+	CommandNote         = 0
+	CommandInstrument   = 1
+	CommandVolumeEffect = 2
+	CommandEffect       = 3
+	CommandVolume       = 4
+	CommandParameter    = 5
 )
 
+// fullCellWidth is the width of a full libopenmpt cell: "C-5 01 v40 A0F"
+const fullCellWidth = 14
+
 // RenderPattern renders the pattern view using a pre-fetched snapshot
-// Muted channels are shown dimmed
-func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palette ColorPalette) string {
+// Only the channels in window are drawn, in the window's density; muted channels are shown dimmed
+func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, channelNames []string, window ChannelWindow, palette ColorPalette) string {
 	if len(snapshot.Rows) == 0 {
 		return "No pattern data available"
 	}
 
 	currentPattern := snapshot.CurrentPattern
 	currentRow := snapshot.CurrentRow
-	channels := snapshot.NumChannels
+	firstChannel := window.First
+	lastChannel := min(window.Last(), snapshot.NumChannels)
+	cellWidth := window.Density.cellWidth()
 
 	// Base styles
 	rowStyle := lipgloss.NewStyle().Foreground(palette.RowNumber)
@@ -34,6 +40,8 @@ func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palett
 	instrumentStyle := lipgloss.NewStyle().Foreground(palette.Instrument)
 	volumeStyle := lipgloss.NewStyle().Foreground(palette.Volume)
 	effectStyle := lipgloss.NewStyle().Foreground(palette.Effect)
+	specialStyle := lipgloss.NewStyle().Foreground(palette.Title)
+	emptyStyle := lipgloss.NewStyle().Foreground(palette.RowNumber)
 	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")) // Dimmed gray
 
 	// Highlight styles (pre-calculated with background)
@@ -46,17 +54,40 @@ func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palett
 	hlInstStyle := instrumentStyle.Copy().Background(palette.CurrentRowBg)
 	hlVolStyle := volumeStyle.Copy().Background(palette.CurrentRowBg)
 	hlEffStyle := effectStyle.Copy().Background(palette.CurrentRowBg)
+	hlSpecialStyle := specialStyle.Copy().Background(palette.CurrentRowBg)
+	hlEmptyStyle := emptyStyle.Copy().Background(palette.CurrentRowBg)
 	hlSepStyle := lipgloss.NewStyle().Foreground(palette.RowNumber).Background(palette.CurrentRowBg)
 
 	var lines []string
 
-	// Header
-	header := fmt.Sprintf("%4s │", "Row")
-	for ch := 0; ch < channels; ch++ {
-		header += fmt.Sprintf(" Ch%-11d │", ch+1)
+	// Header: channel names, with the focused channel highlighted
+	headerStyle := lipgloss.NewStyle().Bold(true)
+	focusStyle := hlRowStyle
+	header := headerStyle.Render(fmt.Sprintf("%4s │", "Row"))
+	for ch := firstChannel; ch < lastChannel; ch++ {
+		label := channelLabel(ch, channelNames, cellWidth)
+		if ch == window.Focus {
+			header += focusStyle.Render(fitWidth(label, cellWidth)) + headerStyle.Render(" │")
+			continue
+		}
+		header += headerStyle.Render(fitWidth(label, cellWidth) + " │")
 	}
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(header))
-	lines = append(lines, strings.Repeat("─", len(header)))
+	ruleWidth := lipgloss.Width(header)
+	if window.Scrolled() {
+		// Channel range, with arrows towards the channels that are off screen
+		left, right := " ", " "
+		if firstChannel > 0 {
+			left = "◀"
+		}
+		if lastChannel < window.Total {
+			right = "▶"
+		}
+		header += rowStyle.Render(fmt.Sprintf(" %sCh %d-%d/%d%s", left, firstChannel+1, lastChannel, window.Total, right))
+	}
+	// Focused channel and its volume/panning
+	header += rowStyle.Render(fmt.Sprintf(" ▸%d %s", window.Focus+1, window.FocusMix))
+	lines = append(lines, header)
+	lines = append(lines, strings.Repeat("─", ruleWidth))
 
 	// Render rows
 	for _, rowStr := range snapshot.Rows {
@@ -65,7 +96,7 @@ func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palett
 
 		// Select styles for this row
 		var (
-			rStyle, nStyle, iStyle, vStyle, eStyle, sepStyle lipgloss.Style
+			rStyle, nStyle, iStyle, vStyle, eStyle, mStyle, dStyle, sepStyle lipgloss.Style
 		)
 
 		if isCurrent {
@@ -74,6 +105,8 @@ func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palett
 			iStyle = hlInstStyle
 			vStyle = hlVolStyle
 			eStyle = hlEffStyle
+			mStyle = hlSpecialStyle
+			dStyle = hlEmptyStyle
 			sepStyle = hlSepStyle
 		} else {
 			rStyle = rowStyle
@@ -81,6 +114,8 @@ func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palett
 			iStyle = instrumentStyle
 			vStyle = volumeStyle
 			eStyle = effectStyle
+			mStyle = specialStyle
+			dStyle = emptyStyle
 			sepStyle = lipgloss.NewStyle().Foreground(palette.RowNumber) // Default separator
 		}
 
@@ -99,74 +134,65 @@ func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palett
 
 		if len(rowStr.Channels) == 0 {
 			// Out of bounds ROW -> Blank channels
-			// But if it's the current row (unlikely for out of bounds, but possible logic), we might want highlight?
-			// Actually, typewriter scrolling means currentRow is always valid, so empty rows are never highlighted.
-			// Just render spaces.
-
-			// PatternCell width calculation:
-			// Note (3) + space + Inst (2) + space + Vol (2) + space + Eff (3) = 13 chars
-			emptyContent := strings.Repeat(" ", 13)
-
-			// We need to replicate the separator structure
-			for ch := 0; ch < channels; ch++ {
+			// Typewriter scrolling means currentRow is always valid, so empty rows are never highlighted
+			emptyContent := strings.Repeat(" ", cellWidth)
+			for ch := firstChannel; ch < lastChannel; ch++ {
 				renderedChannels = append(renderedChannels, sepStyle.Render(emptyContent))
 			}
 		} else {
 			// Normal Valid Row
-			for ch := 0; ch < channels; ch++ {
+			for ch := firstChannel; ch < lastChannel; ch++ {
 				if ch >= len(rowStr.Channels) {
 					break
 				}
 				cell := rowStr.Channels[ch]
 
-				// Check if this channel is muted
-				isMuted := ch < len(mutedChannels) && mutedChannels[ch]
-
-				// Select styles based on mute status
-				var chNStyle, chIStyle, chVStyle, chEStyle lipgloss.Style
-				if isMuted {
-					// Use dimmed style for all components
-					chNStyle = mutedStyle
-					chIStyle = mutedStyle
-					chVStyle = mutedStyle
-					chEStyle = mutedStyle
-				} else {
-					// Use normal or highlighted styles
-					chNStyle = nStyle
-					chIStyle = iStyle
-					chVStyle = vStyle
-					chEStyle = eStyle
+				// Muted channels are dimmed as a whole
+				if ch < len(mutedChannels) && mutedChannels[ch] {
+					renderedChannels = append(renderedChannels, mutedStyle.Render(cellText(cell, cellWidth)))
+					continue
 				}
 
-				nStr := formatNote(cell.Note)
-				iStr := formatInstrument(cell.Instrument)
-				vStr := formatVolume(cell.Volume)
-				eStr := formatEffect(cell.Effect)
-
-				// Render each component with its style (which might have BG)
-				// We join them with spaces, which also need the BG if highlighted
-				space := sepStyle.Render(" ")
+				// Color each character by libopenmpt's highlight class, one Render per run
+				styleFor := func(class byte) lipgloss.Style {
+					switch class {
+					case player.HighlightNote:
+						return nStyle
+					case player.HighlightSpecialNote:
+						return mStyle
+					case player.HighlightInstrument:
+						return iStyle
+					case player.HighlightVolumeEffect, player.HighlightVolume:
+						return vStyle
+					case player.HighlightEffect, player.HighlightParameter:
+						return eStyle
+					case player.HighlightEmpty:
+						return dStyle
+					}
+					return sepStyle
+				}
 
-				part := fmt.Sprintf("%s%s%s%s%s%s%s",
-					chNStyle.Render(nStr), space,
-					chIStyle.Render(iStr), space,
-					chVStyle.Render(vStr), space,
-					chEStyle.Render(eStr),
-				)
+				text := cellText(cell, cellWidth)
+				var part strings.Builder
+				start := 0
+				for k := 1; k <= len(text); k++ {
+					if k < len(text) && highlightAt(cell.Highlight, k) == highlightAt(cell.Highlight, start) {
+						continue
+					}
+					part.WriteString(styleFor(highlightAt(cell.Highlight, start)).Render(text[start:k]))
+					start = k
+				}
 
-				renderedChannels = append(renderedChannels, part)
+				renderedChannels = append(renderedChannels, part.String())
 			}
 		}
 
-		// Join everything with separators
-		// Note: The separators " | " need the background if highlighted
-		sep := sepStyle.Render(" │ ")
-
-		content := strings.Join(renderedChannels, sep)
+		// Each cell is followed by a separator, which needs the background if highlighted
+		sep := sepStyle.Render(" │")
 
 		// Final line assembly
-		// "ROW | CH1 | CH2 |"
-		line := fmt.Sprintf("%s%s%s%s", renderedRow, sep, content, sep)
+		// "ROW │CH1 │CH2 │"
+		line := renderedRow + sep + strings.Join(renderedChannels, sep) + sep
 
 		// Add pattern info if current
 		if isCurrent {
@@ -181,12 +207,20 @@ func RenderPattern(snapshot player.PatternSnapshot, mutedChannels []bool, palett
 
 var noteNames = []string{"C-", "C#", "D-", "D#", "E-", "F-", "F#", "G-", "G#", "A-", "A#", "B-"}
 
+// formatNote names a note value the way trackers display it
 func formatNote(note int) string {
-	if note == 0 || note == 255 {
+	switch note {
+	case player.NoteNone:
 		return "..."
+	case player.NoteFade:
+		return "~~~"
+	case player.NoteCut:
+		return "^^^"
+	case player.NoteOff:
+		return "==="
 	}
-	if note == 254 {
-		return "===" // Note off
+	if note < player.NoteMin || note > player.NoteMax {
+		return "..."
 	}
 
 	octave := (note - 1) / 12
@@ -195,23 +229,33 @@ func formatNote(note int) string {
 	return fmt.Sprintf("%s%d", noteNames[noteIdx], octave)
 }
 
-func formatInstrument(inst int) string {
-	if inst == 0 {
-		return ".."
+// cellText returns the native cell text padded or cut to width
+// Narrower densities are prefixes of the full cell; cells are ASCII, so byte
+// offsets line up with the highlight string
+func cellText(cell player.PatternCell, width int) string {
+	text := cell.Text
+	if text == "" {
+		text = formatNote(cell.Note) + " .. ... ..."
+	}
+	if len(text) > width {
+		return text[:width]
 	}
-	return fmt.Sprintf("%02X", inst)
+	return text + strings.Repeat(" ", width-len(text))
 }
 
-func formatVolume(vol int) string {
-	if vol == 0 {
-		return ".."
+// fitWidth pads or cuts s to exactly width columns
+func fitWidth(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
 	}
-	return fmt.Sprintf("%02X", vol)
+	return s + strings.Repeat(" ", width-len(runes))
 }
 
-func formatEffect(eff int) string {
-	if eff == 0 {
-		return "..."
+// highlightAt returns the highlight class for byte i of a cell (space past the end)
+func highlightAt(highlight string, i int) byte {
+	if i < len(highlight) {
+		return highlight[i]
 	}
-	return fmt.Sprintf("%03X", eff)
+	return ' '
 }
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MainView selects what fills the main area under the meters
+type MainView int
+
+const (
+	ViewTracker   MainView = iota // Classic pattern grid
+	ViewPianoRoll                 // Scrolling piano roll of the whole song
+)
+
+// RollColorMode selects what the piano roll colors notes by
+type RollColorMode int
+
+const (
+	RollByChannel RollColorMode = iota
+	RollByInstrument
+)
+
+// rollPlayheadFraction places the playhead a third of the way in, leaving more room for what's coming
+const rollPlayheadFraction = 3
+
+// rollSlot is what one channel plays on one row of the flattened song
+type rollSlot struct {
+	note       uint8 // 0 = silent
+	instrument uint8 // Last instrument used on the channel
+}
+
+// PianoRoll is the song flattened along the order list into one row-indexed timeline
+type PianoRoll struct {
+	slots      [][]rollSlot // slots[row][channel]
+	orderStart []int        // Timeline row each order entry starts at (-1 for markers)
+	low, high  int          // Lowest and highest note used (0 if the song has no notes)
+}
+
+// BuildPianoRoll walks the order list once, tracking the sounding note per channel
+// Notes sound from their note-on until a note-off, cut, fade or the next note on the channel.
+// Every "---" starts a new segment (a later subsong, or orders reached by a jump), which begins
+// silent; "+++" entries are skipped.
+func BuildPianoRoll(mod *player.Module, orders []int, numChannels int) *PianoRoll {
+	roll := &PianoRoll{orderStart: make([]int, len(orders))}
+	notes := make([]uint8, numChannels)
+	instruments := make([]uint8, numChannels)
+
+	for i, pattern := range orders {
+		roll.orderStart[i] = -1
+		if pattern == player.OrderSkip {
+			continue
+		}
+		if pattern == player.OrderEnd {
+			clear(notes)
+			clear(instruments)
+			continue
+		}
+
+		roll.orderStart[i] = len(roll.slots)
+		for _, row := range mod.GetCachedPattern(pattern).Rows {
+			for ch, cell := range row.Channels {
+				if ch >= numChannels {
+					break
+				}
+				if cell.Instrument > 0 && cell.Instrument < 256 {
+					instruments[ch] = uint8(cell.Instrument)
+				}
+				switch {
+				case cell.Note >= player.NoteMin && cell.Note <= player.NoteMax:
+					notes[ch] = uint8(cell.Note)
+					if roll.low == 0 || cell.Note < roll.low {
+						roll.low = cell.Note
+					}
+					if cell.Note > roll.high {
+						roll.high = cell.Note
+					}
+				case cell.Note == player.NoteOff, cell.Note == player.NoteCut, cell.Note == player.NoteFade:
+					notes[ch] = 0
+				}
+			}
+
+			slots := make([]rollSlot, numChannels)
+			for ch := range slots {
+				slots[ch] = rollSlot{note: notes[ch], instrument: instruments[ch]}
+			}
+			roll.slots = append(roll.slots, slots)
+		}
+	}
+
+	return roll
+}
+
+// Position maps an order/row to a timeline row, or -1 if the order isn't part of the timeline
+func (r *PianoRoll) Position(order, row int) int {
+	if r == nil || order < 0 || order >= len(r.orderStart) || r.orderStart[order] < 0 {
+		return -1
+	}
+	return r.orderStart[order] + row
+}
+
+// RenderPianoRoll draws the timeline around playRow: time runs left to right, pitch bottom to top
+// Each text line holds two semitones using half blocks; the first line is an order ruler
+func RenderPianoRoll(roll *PianoRoll, playRow int, colorMode RollColorMode, mutedChannels []bool, width, height int, palette ColorPalette) string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	rulerStyle := lipgloss.NewStyle().Foreground(palette.RowNumber)
+	playheadStyle := lipgloss.NewStyle().Foreground(palette.CurrentRow).Background(palette.CurrentRowBg)
+	mutedColor := lipgloss.Color("240")
+
+	if roll == nil {
+		// Still being built in the background
+		return renderBuilding(labelStyle.Render("Ord ")+" │", height, palette)
+	}
+
+	cols := width - 6 // Same label column as the VU meters ("VU▲ │")
+	if cols < 8 {
+		cols = 8
+	}
+	if height < 2 {
+		height = 2
+	}
+	pitchLines := height - 1
+	playCol := cols / rollPlayheadFraction
+	firstRow := playRow - playCol
+
+	// Fit the used note range, centred, two semitones per line
+	span := pitchLines * 2
+	bottom := player.NoteMin
+	if roll.high > 0 {
+		bottom = (roll.low+roll.high)/2 - span/2
+	}
+	if bottom+span-1 > player.NoteMax {
+		bottom = player.NoteMax - span + 1
+	}
+	if bottom < player.NoteMin {
+		bottom = player.NoteMin
+	}
+	// Start on an even offset from C-0 so C lines stay put while scrolling
+	bottom -= (bottom - player.NoteMin) % 2
+
+	// colorAt picks the color of the lowest channel playing note on a timeline row
+	colorAt := func(row, note int) (lipgloss.Color, bool) {
+		if row < 0 || row >= len(roll.slots) || note > player.NoteMax {
+			return "", false
+		}
+		for ch, slot := range roll.slots[row] {
+			if int(slot.note) != note {
+				continue
+			}
+			if ch < len(mutedChannels) && mutedChannels[ch] {
+				return mutedColor, true
+			}
+			if colorMode == RollByInstrument {
+				return ChannelColor(int(slot.instrument) - 1), true
+			}
+			return ChannelColor(ch), true
+		}
+		return "", false
+	}
+
+	var lines []string
+
+	// Ruler: order numbers where each order starts
+	ruler := []rune(strings.Repeat(" ", cols))
+	for order, start := range roll.orderStart {
+		col := start - firstRow
+		if start < 0 || col < 0 || col >= cols {
+			continue
+		}
+		for i, c := range fmt.Sprintf("|%03d", order) {
+			if col+i < cols {
+				ruler[col+i] = c
+			}
+		}
+	}
+	lines = append(lines, labelStyle.Render("Ord ")+" │"+rulerStyle.Render(string(ruler)))
+
+	for line := 0; line < pitchLines; line++ {
+		lo := bottom + (pitchLines-1-line)*2
+		hi := lo + 1
+
+		label := "    "
+		if (lo-1)%12 == 0 {
+			label = fmt.Sprintf("%-4s", formatNote(lo))
+		} else if (hi-1)%12 == 0 {
+			label = fmt.Sprintf("%-4s", formatNote(hi))
+		}
+
+		var b strings.Builder
+		b.WriteString(labelStyle.Render(label))
+		b.WriteString(" │")
+
+		// Consecutive cells with the same look are rendered as one run
+		var run strings.Builder
+		var runStyle lipgloss.Style
+		runKey := ""
+		flush := func() {
+			if run.Len() > 0 {
+				b.WriteString(runStyle.Render(run.String()))
+				run.Reset()
+			}
+		}
+
+		for col := 0; col < cols; col++ {
+			row := firstRow + col
+			hiColor, hiOn := colorAt(row, hi)
+			loColor, loOn := colorAt(row, lo)
+
+			cell, style, key := " ", lipgloss.NewStyle(), ""
+			switch {
+			case hiOn && loOn:
+				cell = "▀"
+				style = style.Foreground(hiColor).Background(loColor)
+				key = "b" + string(hiColor) + string(loColor)
+			case hiOn:
+				cell = "▀"
+				style = style.Foreground(hiColor)
+				key = "h" + string(hiColor)
+			case loOn:
+				cell = "▄"
+				style = style.Foreground(loColor)
+				key = "l" + string(loColor)
+			}
+			if col == playCol {
+				if cell == " " {
+					cell = "│"
+				}
+				if !hiOn || !loOn {
+					style = style.Background(palette.CurrentRowBg)
+					if !hiOn && !loOn {
+						style = playheadStyle
+					}
+				}
+				key = "p" + key
+			}
+
+			if key != runKey {
+				flush()
+				runKey, runStyle = key, style
+			}
+			run.WriteString(cell)
+		}
+		flush()
+		lines = append(lines, b.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderBuilding stands in for a song-wide view while it is built, keeping its height
+func renderBuilding(label string, height int, palette ColorPalette) string {
+	lines := make([]string, max(1, height))
+	lines[0] = label + lipgloss.NewStyle().Foreground(palette.RowNumber).Render("building…")
+	return strings.Join(lines, "\n")
+}
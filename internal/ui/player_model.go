@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/slimewell/GoMod/internal/player"
@@ -15,22 +16,76 @@ import (
 // tickMsg is sent periodically to update the UI
 type tickMsg time.Time
 
+// moduleLoadedMsg reports that the module is loaded and playing
+type moduleLoadedMsg struct{}
+
+// songViewsMsg carries the song-wide views built in the background once playback has started
+type songViewsMsg struct {
+	module  *player.Module
+	roll    *PianoRoll
+	harmony *Harmony
+	minimap *Minimap
+}
+
 // PlayerModel handles the music playback view
 type PlayerModel struct {
 	audioContext      *oto.Context
 	module            *player.Module
 	player            *player.Player
-	filename          string
+	source            ModuleSource
 	stereoSep         int
 	width             int
 	height            int
 	visibleRows       int
 	palette           ColorPalette
 	instruments       []player.Instrument
+	warnings          []string
+	messageLines      []string
+	sampleNameLines   []string
+	statsLines        []string // Built on first use, since it walks the whole song
+	textPanel         TextPanel
+	textScroll        int
+	textAutoScroll    bool
+	orders            []int
+	currentOrder      int
+	showOrders        bool
+	orderCursor       int
+	orderCursorMoved  bool // Cursor was moved by the user and no longer follows playback
+	browsing          bool // Follow off: the pattern view shows the order cursor and browseRow
+	browseRow         int
+	searchTyping      bool // The find prompt has the keyboard
+	searchInput       string
+	searchErr         string
+	search            *searchResults // Open results panel, or nil
+	showMinimap       bool
+	minimap           *Minimap
+	vizMode           VizMode
+	tapLeft           []float32 // Latest synced output window, shared by the visualizers
+	tapRight          []float32
+	spectrum          *SpectrumAnalyzer
+	correlation       float64
+	showKeyboard      bool
+	mainView          MainView
+	roll              *PianoRoll
+	rollRow           int
+	rollColor         RollColorMode
+	showHarmony       bool
+	harmony           *Harmony
+	channelFirst      int // First channel shown in the pattern and meters
+	channelFocus      int // Channel that mute/solo/volume/pan keys act on
+	channelNames      []string
+	density           PatternDensity
+	notes             noteTracker
 	activeInstruments map[int]int
 	patternData       player.PatternSnapshot
-	lastVolumes       []float64
+	meterMode         MeterMode
+	vuMono            *meterBallistics
+	vuLeft            *meterBallistics
+	vuRight           *meterBallistics
+	master            *masterMeter
 	currentTime       float64
+	fadeOpts          player.FadeOptions
+	sleepAt           time.Time // Sleep timer deadline, handed to the player once it exists
 	ctx               context.Context
 	cancel            context.CancelFunc
 	ready             bool
@@ -38,20 +93,33 @@ type PlayerModel struct {
 }
 
 // NewPlayerModel creates a new player model
-func NewPlayerModel(audioContext *oto.Context, filename string, stereoSep int, themeName string, width, height int) *PlayerModel {
+func NewPlayerModel(audioContext *oto.Context, source ModuleSource, config Config, width, height int) *PlayerModel {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &PlayerModel{
+	m := &PlayerModel{
 		audioContext:      audioContext,
-		filename:          filename,
-		stereoSep:         stereoSep,
-		palette:           GetPalette(themeName),
+		source:            source,
+		stereoSep:         config.StereoSep,
+		palette:           GetPalette(config.Theme),
 		activeInstruments: make(map[int]int),
 		visibleRows:       21,
+		showOrders:        true,
+		tapLeft:           make([]float32, fftSize),
+		tapRight:          make([]float32, fftSize),
+		spectrum:          NewSpectrumAnalyzer(config.spectrumBands()),
+		vuMono:            newMeterBallistics(config.vuDecay(), config.vuPeakHold()),
+		vuLeft:            newMeterBallistics(config.vuDecay(), config.vuPeakHold()),
+		vuRight:           newMeterBallistics(config.vuDecay(), config.vuPeakHold()),
+		master:            newMasterMeter(config.vuDecay(), config.vuPeakHold()),
+		fadeOpts:          config.fadeOptions(),
 		width:             width,
 		height:            height,
 		ctx:               ctx,
 		cancel:            cancel,
 	}
+	if config.Sleep > 0 {
+		m.sleepAt = time.Now().Add(config.Sleep)
+	}
+	return m
 }
 
 // Init initializes the player
@@ -63,7 +131,7 @@ func (m *PlayerModel) Init() tea.Cmd {
 }
 
 func (m *PlayerModel) loadModule() tea.Msg {
-	mod, err := player.LoadModule(m.filename)
+	mod, err := m.source.Load()
 	if err != nil {
 		return errMsg{err}
 	}
@@ -72,17 +140,29 @@ func (m *PlayerModel) loadModule() tea.Msg {
 	_ = mod.SetInterpolationFilter(8)
 
 	m.instruments = mod.GetInstrumentList()
+	m.warnings = mod.Warnings()
+
+	metadata := mod.GetMetadata()
+	m.messageLines = messageLines(metadata.Message, metadata.MessageRaw)
+	m.sampleNameLines = sampleNameLines(mod.GetRawInstrumentNames(), mod.GetRawSampleNames())
+	m.orders = mod.GetOrderList()
+	m.channelNames = mod.GetChannelNames()
 
 	// Use shared audio context
 	p, err := player.NewPlayer(m.audioContext, mod)
 	if err != nil {
 		return errMsg{err}
 	}
+	p.SetFadeOptions(m.fadeOpts)
+	p.SetSleepTimer(m.sleepAt)
 
 	if err := p.Play(m.ctx); err != nil {
 		return errMsg{err}
 	}
 
+	// Warm the pattern cache for search and the piano roll while the song plays
+	go mod.PrefetchPatterns()
+
 	m.module = mod
 	m.player = p
 	m.ready = true
@@ -90,7 +170,21 @@ func (m *PlayerModel) loadModule() tea.Msg {
 	// Recalculate layout now that we have instruments
 	m.recalculateVisibleRows()
 
-	return nil
+	return moduleLoadedMsg{}
+}
+
+// buildSongViews walks the whole song for the piano roll, harmony and minimap without holding up playback
+func (m *PlayerModel) buildSongViews() tea.Cmd {
+	mod, orders := m.module, m.orders
+	return func() tea.Msg {
+		roll := BuildPianoRoll(mod, orders, mod.GetNumChannels())
+		return songViewsMsg{
+			module:  mod,
+			roll:    roll,
+			harmony: BuildHarmony(roll),
+			minimap: BuildMinimap(mod, orders, mod.GetNumChannels()),
+		}
+	}
 }
 
 func (m *PlayerModel) tickCmd() tea.Msg {
@@ -102,6 +196,17 @@ func (m *PlayerModel) tickCmd() tea.Msg {
 func (m *PlayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.searchTyping {
+			m.updateSearchInput(msg)
+			return m, nil
+		}
+		if m.textPanel != PanelNone && m.updateTextPanel(msg.String()) {
+			return m, nil
+		}
+		if m.search != nil && m.updateSearchResults(msg.String()) {
+			return m, nil
+		}
+
 		switch msg.String() {
 		// Note: Global keys like q/ctrl+c are handled by AppModel, 
 		// but we handle player controls here.
@@ -112,6 +217,236 @@ func (m *PlayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "z":
+			m.cycleSleepTimer()
+			return m, nil
+
+		case "e":
+			if m.player != nil {
+				m.player.SetStopAfterCurrent(!m.player.StopStatus().AfterCurrent)
+			}
+			return m, nil
+
+		case "m":
+			m.toggleTextPanel(PanelMessage)
+			return m, nil
+
+		case "n":
+			m.toggleTextPanel(PanelSampleNames)
+			return m, nil
+
+		case "S":
+			if m.statsLines == nil && m.module != nil {
+				m.statsLines = StatsLines(m.module.GetStats())
+			}
+			m.toggleTextPanel(PanelStats)
+			return m, nil
+
+		case "?":
+			m.toggleTextPanel(PanelHelp)
+			return m, nil
+
+		case "o":
+			m.showOrders = !m.showOrders
+			m.scrollChannels()
+			return m, nil
+
+		case "d":
+			m.density = m.density.next()
+			m.scrollChannels()
+			return m, nil
+
+		case "left", "right":
+			if m.module != nil {
+				if msg.String() == "left" {
+					m.channelFocus--
+				} else {
+					m.channelFocus++
+				}
+				m.channelFocus = max(0, min(m.channelFocus, m.module.GetNumChannels()-1))
+				m.scrollChannels()
+			}
+			return m, nil
+
+		case "x":
+			if m.player != nil {
+				m.player.InstantMute(m.channelFocus)
+			}
+			return m, nil
+
+		case "s":
+			if m.player != nil {
+				m.player.InstantSolo(m.channelFocus)
+			}
+			return m, nil
+
+		case "{", "}":
+			if m.player != nil {
+				delta := channelVolumeStep
+				if msg.String() == "{" {
+					delta = -delta
+				}
+				m.player.InstantChannelVolume(m.channelFocus, delta)
+			}
+			return m, nil
+
+		case "<", ">":
+			if m.player != nil {
+				delta := channelPanningStep
+				if msg.String() == "<" {
+					delta = -delta
+				}
+				m.player.InstantChannelPanning(m.channelFocus, delta)
+			}
+			return m, nil
+
+		case "u":
+			m.meterMode = m.meterMode.next()
+			m.recalculateVisibleRows()
+			return m, nil
+
+		case "p":
+			m.showKeyboard = !m.showKeyboard
+			m.notes = noteTracker{} // Rebuilt from the piano roll on the next tick
+			m.recalculateVisibleRows()
+			return m, nil
+
+		case "h":
+			m.showHarmony = !m.showHarmony
+			m.recalculateVisibleRows()
+			return m, nil
+
+		case "r":
+			if m.mainView == ViewPianoRoll {
+				m.mainView = ViewTracker
+			} else {
+				m.mainView = ViewPianoRoll
+			}
+			return m, nil
+
+		case "c":
+			if m.rollColor == RollByChannel {
+				m.rollColor = RollByInstrument
+			} else {
+				m.rollColor = RollByChannel
+			}
+			return m, nil
+
+		case "v":
+			m.vizMode = m.vizMode.next()
+			m.recalculateVisibleRows()
+			return m, nil
+
+		case "f":
+			m.toggleBrowsing()
+			return m, nil
+
+		case "M":
+			m.showMinimap = !m.showMinimap
+			m.recalculateVisibleRows()
+			return m, m.mouseCmd()
+
+		case "shift+left", "shift+right":
+			// Move the order cursor one minimap column at a time
+			if m.showMinimap && len(m.orders) > 0 {
+				if !m.orderCursorMoved {
+					m.orderCursor = m.currentOrder
+					m.orderCursorMoved = true
+				}
+				step := m.minimap.ColumnStep(m.width)
+				if msg.String() == "shift+left" {
+					step = -step
+				}
+				m.orderCursor = max(0, min(m.orderCursor+step, len(m.orders)-1))
+			}
+			return m, nil
+
+		case "/":
+			m.searchTyping = true
+			m.searchInput = ""
+			m.searchErr = ""
+			return m, nil
+
+		case "up", "down", "pgup", "pgdown":
+			if m.browsing {
+				switch msg.String() {
+				case "up":
+					m.moveBrowseRow(-1)
+				case "down":
+					m.moveBrowseRow(1)
+				case "pgup":
+					m.moveBrowseRow(-browsePageRows)
+				case "pgdown":
+					m.moveBrowseRow(browsePageRows)
+				}
+			} else if m.canStep() {
+				// Paused: step one row and hear it
+				switch msg.String() {
+				case "up":
+					m.player.StepRow(-1)
+				case "down":
+					m.player.StepRow(1)
+				}
+			}
+			return m, nil
+
+		case "shift+up", "shift+down":
+			if !m.browsing && m.canStep() {
+				if msg.String() == "shift+up" {
+					m.player.StepTick(-1)
+				} else {
+					m.player.StepTick(1)
+				}
+			}
+			return m, nil
+
+		case ",", ".":
+			if m.browsing {
+				// Jump between patterns, skipping +++ and --- markers
+				dir := 1
+				if msg.String() == "," {
+					dir = -1
+				}
+				if order := m.stepOrder(m.orderCursor, dir); order >= 0 {
+					m.orderCursor = order
+					m.browseRow = min(m.browseRow, m.browsePatternRows()-1)
+				}
+				return m, nil
+			}
+			if m.orderCursorVisible() {
+				if !m.orderCursorMoved {
+					m.orderCursor = m.currentOrder
+					m.orderCursorMoved = true
+				}
+				if msg.String() == "," && m.orderCursor > 0 {
+					m.orderCursor--
+				} else if msg.String() == "." && m.orderCursor < len(m.orders)-1 {
+					m.orderCursor++
+				}
+			}
+			return m, nil
+
+		case "esc":
+			// Drop the order selection and follow playback again
+			m.orderCursorMoved = false
+			m.browsing = false
+			return m, nil
+
+		case "enter":
+			// Play from the browse cursor and follow again
+			if m.browsing && m.player != nil {
+				m.player.SeekOrderRow(m.orderCursor, m.browseRow)
+				m.browsing = false
+				m.orderCursorMoved = false
+				return m, nil
+			}
+			// Jump playback to the selected order
+			if m.orderCursorVisible() && m.orderCursorMoved && m.player != nil {
+				m.player.SeekOrderRow(m.orderCursor, 0)
+				m.orderCursorMoved = false
+			}
+			return m, nil
+
 		case "[":
 			if m.player != nil && m.module != nil {
 				m.stereoSep -= 10
@@ -149,8 +484,9 @@ func (m *PlayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case "-": ch = 10
 				case "=": ch = 11
 				}
+				// Keys count from the first visible channel
 				if ch != -1 && m.player != nil {
-					m.player.InstantMute(ch)
+					m.player.InstantMute(m.channelFirst + ch)
 				}
 			}
 			return m, nil
@@ -173,29 +509,66 @@ func (m *PlayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case "+": ch = 11
 				}
 				if ch != -1 && m.player != nil {
-					m.player.InstantSolo(ch)
+					m.player.InstantSolo(m.channelFirst + ch)
 				}
 			}
 			return m, nil
 		}
 
+	case tea.MouseMsg:
+		// Clicking the minimap seeks to the start of that order
+		if m.ready && m.showMinimap && m.player != nil &&
+			msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			top := m.minimapTop()
+			if msg.Y >= top && msg.Y < top+m.minimap.Rows() {
+				// Columns start after the "Ch01 │" label
+				if order := m.minimap.OrderAt(msg.X-6, m.width); order >= 0 {
+					m.player.SeekOrderRow(order, 0)
+					m.browsing = false
+					m.orderCursorMoved = false
+				}
+			}
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.recalculateVisibleRows()
+		m.scrollChannels()
+		return m, nil
+
+	case moduleLoadedMsg:
+		return m, m.buildSongViews()
+
+	case songViewsMsg:
+		if msg.module == m.module {
+			m.roll = msg.roll
+			m.harmony = msg.harmony
+			m.minimap = msg.minimap
+			m.recalculateVisibleRows()
+		}
 		return m, nil
 
 	case tickMsg:
 		if m.ready {
 			var currentRow, currentPattern int
 			var currentVolumes []float64
+			var synced player.SyncState
 
-			if m.player != nil && m.player.IsPlaying() {
+			if m.player != nil {
+				// While paused these hold the heard position, or the row last stepped into
 				m.currentTime = m.player.GetSyncedTime()
-				currentPattern, currentRow, currentVolumes = m.player.GetSyncedState()
+				synced = m.player.GetSyncedPosition()
+				currentPattern, currentRow, currentVolumes = synced.Pattern, synced.Row, synced.ChannelVolumes
+				m.currentOrder = synced.Order
 			} else {
 				currentRow = m.module.GetCurrentRow()
 				currentPattern = m.module.GetCurrentPattern()
+				m.currentOrder = m.module.GetCurrentOrder()
+			}
+			if !m.orderCursorMoved {
+				m.orderCursor = m.currentOrder
 			}
 
 			newActives := m.module.GetRowInstruments(currentRow)
@@ -212,25 +585,40 @@ func (m *PlayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			m.patternData = m.module.GetPatternView(currentPattern, currentRow, m.module.GetNumChannels(), m.visibleRows, currentVolumes)
-
-			newVolumes := m.patternData.ChannelVolumes
-			if len(m.lastVolumes) != len(newVolumes) {
-				m.lastVolumes = make([]float64, len(newVolumes))
+			if pattern := m.browsePattern(); m.browsing && pattern >= 0 {
+				// Follow off: show the browse cursor, with live meters
+				m.patternData = m.module.GetPatternView(pattern, m.browseRow, m.module.GetNumChannels(), m.visibleRows, currentVolumes)
+			} else {
+				m.patternData = m.module.GetPatternView(currentPattern, currentRow, m.module.GetNumChannels(), m.visibleRows, currentVolumes)
+			}
+			m.rollRow = m.roll.Position(m.currentOrder, currentRow)
+			if m.showKeyboard {
+				m.notes.update(m.module, m.roll, m.patternData.NumChannels, m.currentOrder, currentPattern, currentRow)
 			}
 
-			decayRate := 0.92
-			for i := range newVolumes {
-				if newVolumes[i] >= m.lastVolumes[i] {
-					m.lastVolumes[i] = newVolumes[i]
-				} else {
-					m.lastVolumes[i] = m.lastVolumes[i] * decayRate
-					if m.lastVolumes[i] < 0.001 {
-						m.lastVolumes[i] = 0
-					}
+			// Meter ballistics (release and peak hold) run on wall-clock time
+			now := time.Time(msg)
+			m.vuMono.update(m.patternData.ChannelVolumes, now)
+			m.vuLeft.update(synced.ChannelLeft, now)
+			m.vuRight.update(synced.ChannelRight, now)
+			m.patternData.ChannelVolumes = m.vuMono.levels
+
+			if m.player != nil {
+				// While paused this is a no-op, so the last captured window stays on screen
+				m.player.GetSyncedSamples(m.tapLeft, m.tapRight)
+				// Peak over roughly one UI frame of audio
+				m.master.update(m.tapLeft[fftSize-masterWindow:], m.tapRight[fftSize-masterWindow:], now)
+				switch m.vizMode {
+				case VizSpectrum:
+					m.spectrum.Update(m.tapLeft, m.tapRight)
+				case VizVectorscope:
+					m.correlation = smoothCorrelation(m.correlation, stereoCorrelation(m.tapLeft, m.tapRight))
 				}
 			}
-			m.patternData.ChannelVolumes = m.lastVolumes
+
+			if m.textPanel != PanelNone && m.textAutoScroll {
+				m.textScroll = m.autoScrollOffset(m.module.GetMetadata().Duration)
+			}
 		}
 		return m, m.tickCmd
 
@@ -242,6 +630,399 @@ func (m *PlayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// browsePageRows is how far PgUp/PgDn move the browse cursor
+const browsePageRows = 16
+
+// toggleBrowsing switches follow mode off (starting at the playhead) or back on
+func (m *PlayerModel) toggleBrowsing() {
+	if m.browsing {
+		m.browsing = false
+		m.orderCursorMoved = false
+		return
+	}
+	if len(m.orders) == 0 {
+		return
+	}
+	m.browsing = true
+	m.orderCursor = max(0, min(m.currentOrder, len(m.orders)-1))
+	m.orderCursorMoved = true
+	m.browseRow = m.patternData.CurrentRow
+	if m.browsePattern() < 0 {
+		// Playhead is on a marker: start at the first playable order
+		m.orderCursor = m.stepOrder(-1, 1)
+		m.browseRow = 0
+	}
+}
+
+// browsePattern returns the pattern under the order cursor, or -1 for markers
+func (m *PlayerModel) browsePattern() int {
+	if m.orderCursor < 0 || m.orderCursor >= len(m.orders) {
+		return -1
+	}
+	pattern := m.orders[m.orderCursor]
+	if pattern == player.OrderSkip || pattern == player.OrderEnd {
+		return -1
+	}
+	return pattern
+}
+
+// browsePatternRows returns the row count of the pattern under the order cursor
+func (m *PlayerModel) browsePatternRows() int {
+	pattern := m.browsePattern()
+	if pattern < 0 {
+		return 1
+	}
+	return max(1, len(m.module.GetCachedPattern(pattern).Rows))
+}
+
+// stepOrder returns the next playable order from order in direction dir, or -1 if there is none
+func (m *PlayerModel) stepOrder(order, dir int) int {
+	for i := order + dir; i >= 0 && i < len(m.orders); i += dir {
+		if m.orders[i] != player.OrderSkip && m.orders[i] != player.OrderEnd {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveBrowseRow moves the browse cursor by delta rows, carrying into neighbouring orders
+func (m *PlayerModel) moveBrowseRow(delta int) {
+	m.browseRow += delta
+	for m.browseRow < 0 {
+		prev := m.stepOrder(m.orderCursor, -1)
+		if prev < 0 {
+			m.browseRow = 0
+			return
+		}
+		m.orderCursor = prev
+		m.browseRow += m.browsePatternRows()
+	}
+	for m.browseRow >= m.browsePatternRows() {
+		next := m.stepOrder(m.orderCursor, 1)
+		if next < 0 {
+			m.browseRow = m.browsePatternRows() - 1
+			return
+		}
+		m.browseRow -= m.browsePatternRows()
+		m.orderCursor = next
+	}
+}
+
+// Typing reports whether the player wants every key as text (the find prompt)
+func (m *PlayerModel) Typing() bool {
+	return m.searchTyping
+}
+
+// transportState is the player's transport state, loading until the player exists
+func (m *PlayerModel) transportState() player.TransportState {
+	if m.player == nil {
+		return player.TransportLoading
+	}
+	return m.player.State()
+}
+
+// canStep reports whether row/tick steps are available (paused, or at the end of the song)
+func (m *PlayerModel) canStep() bool {
+	st := m.transportState()
+	return st == player.TransportPaused || st == player.TransportEnded
+}
+
+// updateSearchInput edits the find prompt; enter runs the search and opens the results
+func (m *PlayerModel) updateSearchInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.searchTyping = false
+	case tea.KeyBackspace:
+		if runes := []rune(m.searchInput); len(runes) > 0 {
+			m.searchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.searchInput += " "
+	case tea.KeyRunes:
+		m.searchInput += string(msg.Runes)
+	case tea.KeyEnter:
+		query, err := parseSearchQuery(m.searchInput)
+		if err != nil {
+			m.searchErr = err.Error()
+			return
+		}
+		m.searchTyping = false
+		m.search = &searchResults{
+			query: strings.Join(strings.Fields(m.searchInput), " "),
+			hits:  searchSong(m.module, m.orders, query),
+		}
+		m.scrollChannels()
+		m.jumpToHit()
+	}
+}
+
+// updateSearchResults moves through the hits while the results panel is open
+// Returns false for keys the panel doesn't use, so player controls keep working
+func (m *PlayerModel) updateSearchResults(key string) bool {
+	visible := m.visibleRows - 1
+	switch key {
+	case "esc":
+		m.search = nil
+		m.scrollChannels()
+		return true
+	case "enter":
+		// Seek playback to the selected hit and follow it
+		if len(m.search.hits) > 0 && m.player != nil {
+			hit := m.search.hits[m.search.selected]
+			m.player.SeekOrderRow(hit.Order, hit.Row)
+			m.browsing = false
+			m.orderCursorMoved = false
+		}
+		return true
+	case "up", "k":
+		m.search.selected--
+	case "down", "j":
+		m.search.selected++
+	case "pgup":
+		m.search.selected -= visible
+	case "pgdown":
+		m.search.selected += visible
+	case "home", "g":
+		m.search.selected = 0
+	case "end", "G":
+		m.search.selected = len(m.search.hits) - 1
+	default:
+		return false
+	}
+	m.search.selected = max(0, min(m.search.selected, len(m.search.hits)-1))
+	m.jumpToHit()
+	return true
+}
+
+// jumpToHit shows the selected hit in the pattern view (follow off) and focuses its channel
+func (m *PlayerModel) jumpToHit() {
+	if m.search == nil || len(m.search.hits) == 0 {
+		return
+	}
+	hit := m.search.hits[m.search.selected]
+	m.browsing = true
+	m.orderCursor = hit.Order
+	m.orderCursorMoved = true
+	m.browseRow = hit.Row
+	m.channelFocus = hit.Channel
+	m.scrollChannels()
+}
+
+// toggleTextPanel opens the given text overlay, or closes it if it is already open
+func (m *PlayerModel) toggleTextPanel(panel TextPanel) {
+	if m.textPanel == panel {
+		m.textPanel = PanelNone
+		return
+	}
+	m.textPanel = panel
+	m.textScroll = 0
+}
+
+// updateTextPanel handles scrolling keys while a text overlay is open
+// Returns false for keys the overlay doesn't use, so player controls keep working
+func (m *PlayerModel) updateTextPanel(key string) bool {
+	lines := m.textPanelLines()
+	visible := m.textPanelHeight() - 4
+
+	switch key {
+	case "esc":
+		m.textPanel = PanelNone
+	case "up", "k":
+		m.textScroll--
+		m.textAutoScroll = false
+	case "down", "j":
+		m.textScroll++
+		m.textAutoScroll = false
+	case "pgup":
+		m.textScroll -= visible
+		m.textAutoScroll = false
+	case "pgdown":
+		m.textScroll += visible
+		m.textAutoScroll = false
+	case "home", "g":
+		m.textScroll = 0
+		m.textAutoScroll = false
+	case "end", "G":
+		m.textScroll = len(lines)
+		m.textAutoScroll = false
+	case "a":
+		m.textAutoScroll = !m.textAutoScroll
+	default:
+		return false
+	}
+
+	m.textScroll = clampScroll(m.textScroll, len(lines), visible)
+	return true
+}
+
+func (m *PlayerModel) textPanelLines() []string {
+	switch m.textPanel {
+	case PanelSampleNames:
+		return m.sampleNameLines
+	case PanelStats:
+		return m.statsLines
+	case PanelHelp:
+		return helpLines()
+	}
+	return m.messageLines
+}
+
+// textPanelHeight is the space left for the overlay below the header and above the controls
+func (m *PlayerModel) textPanelHeight() int {
+	height := m.height - 2 - 1 - 1 - 1 // Header, spacing, spacing, controls
+	if len(m.warnings) > 0 {
+		height--
+	}
+	if m.showMinimap {
+		height -= m.minimap.Rows()
+	}
+	if height < 6 {
+		height = 6
+	}
+	return height
+}
+
+// autoScrollOffset spreads the text over the song so the last line arrives as the song ends
+func (m *PlayerModel) autoScrollOffset(duration float64) int {
+	visible := m.textPanelHeight() - 4
+	overflow := len(m.textPanelLines()) - visible
+	if overflow <= 0 || duration <= 0 {
+		return 0
+	}
+	progress := m.currentTime / duration
+	if progress > 1 {
+		progress = 1
+	}
+	return int(progress * float64(overflow))
+}
+
+// orderCursorVisible reports whether the order cursor is on screen (order list or minimap)
+func (m *PlayerModel) orderCursorVisible() bool {
+	return len(m.orders) > 0 && (m.showOrders || m.showMinimap)
+}
+
+// mouseCmd turns mouse reporting on only while the minimap can be clicked, so the terminal's
+// own text selection works everywhere else
+func (m *PlayerModel) mouseCmd() tea.Cmd {
+	if m.showMinimap {
+		return tea.EnableMouseCellMotion
+	}
+	return tea.DisableMouse
+}
+
+// minimapTop is the screen line the minimap starts on, just under the header
+func (m *PlayerModel) minimapTop() int {
+	top := lipgloss.Height(RenderHeader(m.module.GetMetadata(), m.source.Name, m.currentTime, m.stereoSep, m.transportState(), m.stopStatus(), m.palette))
+	if len(m.warnings) > 0 {
+		top++
+	}
+	return top
+}
+
+// patternWidth is the width left for the pattern view beside the side panel
+func (m *PlayerModel) patternWidth() int {
+	if m.search != nil {
+		return m.width - searchPanelWidth - 1
+	}
+	if m.showOrders && len(m.orders) > 0 {
+		return m.width - orderListWidth - 1
+	}
+	return m.width
+}
+
+// channelWindow returns the channels visible at the current width
+func (m *PlayerModel) channelWindow() ChannelWindow {
+	total := 0
+	if m.module != nil {
+		total = m.module.GetNumChannels()
+	}
+	density := resolveDensity(m.density, m.patternWidth(), total)
+	return ChannelWindow{
+		First:   m.channelFirst,
+		Count:   channelsThatFit(m.patternWidth(), density),
+		Total:   total,
+		Focus:   m.channelFocus,
+		Density: density,
+	}
+}
+
+// scrollChannels keeps the focused channel on screen after a move or resize
+func (m *PlayerModel) scrollChannels() {
+	w := m.channelWindow()
+	m.channelFirst = scrollChannelWindow(m.channelFirst, m.channelFocus, w.Count, w.Total)
+}
+
+// meterHeight is the number of lines the VU meter block takes in the current mode
+func (m *PlayerModel) meterHeight() int {
+	if m.meterMode == MeterDB {
+		return dbMeterRows + 1 // + master meter
+	}
+	return vuRows + 1
+}
+
+// renderMeters renders the VU meter block for the current mode
+// Only the channels in window are drawn, so the meters line up with the pattern columns
+func (m *PlayerModel) renderMeters(mutedChannels []bool, window ChannelWindow) string {
+	now := time.Now()
+	mutedChannels = windowBools(mutedChannels, window)
+	switch m.meterMode {
+	case MeterStereo:
+		scale := func(values []float64) []float64 {
+			out := make([]float64, len(values))
+			for i, v := range values {
+				out[i] = toPerceptualLevel(v)
+			}
+			return out
+		}
+		meters := RenderStereoMeters(scale(windowFloats(m.vuLeft.levels, window)), scale(windowFloats(m.vuRight.levels, window)),
+			scale(windowFloats(m.vuLeft.peaks, window)), scale(windowFloats(m.vuRight.peaks, window)),
+			[]string{"VU▲", "L R", "VU▼"}, mutedChannels, window.Density.cellWidth(), m.palette)
+		return meters + "\n" + RenderMasterMeter(m.master.ballistics.levels, m.master.ballistics.peaks, m.master.clipping(now), m.width, m.palette)
+	case MeterDB:
+		scale := func(values []float64) []float64 {
+			out := make([]float64, len(values))
+			for i, v := range values {
+				out[i] = toDBLevel(v)
+			}
+			return out
+		}
+		meters := RenderStereoMeters(scale(windowFloats(m.vuLeft.levels, window)), scale(windowFloats(m.vuRight.levels, window)),
+			scale(windowFloats(m.vuLeft.peaks, window)), scale(windowFloats(m.vuRight.peaks, window)),
+			dbRowLabels(), mutedChannels, window.Density.cellWidth(), m.palette)
+		return meters + "\n" + RenderMasterMeter(m.master.ballistics.levels, m.master.ballistics.peaks, m.master.clipping(now), m.width, m.palette)
+	}
+	meters := RenderVUMeters(windowFloats(m.patternData.ChannelVolumes, window), windowFloats(m.vuMono.peaks, window),
+		mutedChannels, window.Density.cellWidth(), m.width, m.palette)
+	return meters + "\n" + RenderMasterMeter(m.master.ballistics.levels, m.master.ballistics.peaks, m.master.clipping(now), m.width, m.palette)
+}
+
+// vizHeight is the number of lines the visualizer panel takes
+func (m *PlayerModel) vizHeight() int {
+	switch m.vizMode {
+	case VizScope:
+		return 2 * scopeRows
+	case VizSpectrum:
+		return spectrumRows
+	case VizVectorscope:
+		return vectorRows
+	}
+	return 0
+}
+
+// renderViz renders the active visualizer panel, or "" if none
+func (m *PlayerModel) renderViz() string {
+	switch m.vizMode {
+	case VizScope:
+		// The scope shows the most recent part of the shared window
+		return RenderOscilloscope(m.tapLeft[fftSize-scopeWindow:], m.tapRight[fftSize-scopeWindow:], m.width, m.palette)
+	case VizSpectrum:
+		return RenderSpectrum(m.spectrum, m.width, m.palette)
+	case VizVectorscope:
+		return RenderVectorscope(m.tapLeft, m.tapRight, m.correlation, m.stereoSep, m.width, m.palette)
+	}
+	return ""
+}
+
 func (m *PlayerModel) recalculateVisibleRows() {
 	// Calculate exact overhead to maximize pattern view
 	
@@ -269,12 +1050,27 @@ func (m *PlayerModel) recalculateVisibleRows() {
 	// Spacing: 1
 	// Instruments: instLines
 	// Spacing: 1
-	// VU Meters: 3
+	// VU Meters: meterHeight (3 classic, more with master meter / dB scale)
 	// Pattern Header (RenderPattern adds 2 lines): 2
 	// Spacing: 1
 	// Controls: 1
 	
-	overhead := 2 + 1 + instLines + 1 + 3 + 2 + 1 + 1
+	overhead := 2 + 1 + instLines + 1 + m.meterHeight() + 2 + 1 + 1
+	if m.vizMode != VizNone {
+		overhead += m.vizHeight()
+	}
+	if m.showKeyboard {
+		overhead += keyboardRows
+	}
+	if m.showHarmony {
+		overhead += harmonyRows
+	}
+	if m.showMinimap {
+		overhead += m.minimap.Rows()
+	}
+	if len(m.warnings) > 0 {
+		overhead++ // Warning line under the header
+	}
 	
 available := m.height - overhead
 	if available < 5 {
@@ -298,7 +1094,7 @@ func (m *PlayerModel) View() string {
 	}
 
 	metadata := m.module.GetMetadata()
-	header := RenderHeader(metadata, m.filename, m.currentTime, m.stereoSep, m.palette)
+	header := RenderHeader(metadata, m.source.Name, m.currentTime, m.stereoSep, m.transportState(), m.stopStatus(), m.palette)
 	activeInstruments := RenderInstrumentsCompact(m.instruments, m.activeInstruments, m.palette)
 
 	mutedChannels := make([]bool, m.patternData.NumChannels)
@@ -307,16 +1103,97 @@ func (m *PlayerModel) View() string {
 	}
 
 	
-	vuMeters := RenderVUMeters(m.patternData.ChannelVolumes, mutedChannels, m.width, m.palette)
-	pattern := RenderPattern(m.patternData, mutedChannels, m.palette)
+	window := m.channelWindow()
+	window.FocusMix = formatChannelMix(m.module.GetChannelVolume(m.channelFocus), m.module.GetChannelPanning(m.channelFocus))
+
+	vuMeters := m.renderMeters(mutedChannels, window)
+	var pattern string
+	if m.mainView == ViewPianoRoll {
+		// Same height as the pattern view: header lines plus rows
+		pattern = RenderPianoRoll(m.roll, m.rollRow, m.rollColor, mutedChannels, m.patternWidth(), m.visibleRows+2, m.palette)
+	} else {
+		pattern = RenderPattern(m.patternData, mutedChannels, m.channelNames, window, m.palette)
+	}
+	if m.search != nil {
+		// Search results take the order list's place
+		results := RenderSearchResults(m.search, m.visibleRows+2, m.palette)
+		pattern = lipgloss.JoinHorizontal(lipgloss.Top, pattern, " ", results)
+	} else if m.showOrders && len(m.orders) > 0 {
+		// Pattern view has two header lines above its rows
+		orderList := RenderOrderList(m.orders, m.currentOrder, m.orderCursor, m.visibleRows+2, m.palette)
+		pattern = lipgloss.JoinHorizontal(lipgloss.Top, pattern, " ", orderList)
+	}
+	// The full key map is behind [?]; this line only has room for the essentials
+	controlKeys := "[?] keys  [q] quit"
+	if m.search != nil {
+		controlKeys = "RESULTS [↑/↓ PgUp/PgDn] hit  [enter] play hit  [/] new search  [esc] close  " + controlKeys
+	} else if m.browsing {
+		controlKeys = "FOLLOW OFF [↑/↓ PgUp/PgDn] row  [,/.] pattern  [enter] play here  [f/esc] follow  " + controlKeys
+	} else if m.canStep() {
+		controlKeys = fmt.Sprintf("STEP tick %d [↑/↓] row  [Shift+↑/↓] tick  ", m.player.GetSyncedPosition().Tick) + controlKeys
+	}
 	controls := lipgloss.NewStyle().
 		Foreground(m.palette.Controls).
-		Render("[q] quit  [space] pause  [[ ]] stereo  [1-9,0,-,=] mute  [Shift+] solo")
+		MaxWidth(m.width).
+		Render(controlKeys + "  [space] pause  [←/→] channel  [x/s] mute/solo  [/] find  [z] sleep  [e] stop after song")
+	if m.searchTyping {
+		// The find prompt replaces the controls line
+		prompt := lipgloss.NewStyle().Foreground(m.palette.Title).Render("Find: ") + m.searchInput + "█"
+		if m.searchErr != "" {
+			prompt += "  " + lipgloss.NewStyle().Foreground(m.palette.CurrentRow).Render(m.searchErr)
+		} else {
+			prompt += lipgloss.NewStyle().Foreground(m.palette.Controls).Render("  e.g. C-5 with i03, A0F, fx Dxx, vol v40  [enter] find  [esc] cancel")
+		}
+		controls = prompt
+	}
 
 	var sections []string
 	sections = append(sections, header)
+	if warnings := RenderWarnings(m.warnings, m.width, m.palette); warnings != "" {
+		sections = append(sections, warnings)
+	}
+	if m.showMinimap {
+		cursor := -1
+		if m.orderCursorMoved {
+			cursor = m.orderCursor
+		}
+		if minimap := RenderMinimap(m.minimap, m.channelWindow(), m.currentOrder, cursor, m.width, m.palette); minimap != "" {
+			sections = append(sections, minimap)
+		}
+	}
+
+	if m.textPanel != PanelNone {
+		title := "Song Message"
+		switch m.textPanel {
+		case PanelSampleNames:
+			title = "Instrument & Sample Names"
+		case PanelStats:
+			title = "Song Statistics"
+		case PanelHelp:
+			title = "Keys"
+		}
+		panel := RenderTextPanel(title, m.textPanelLines(), m.textScroll, m.textAutoScroll, m.width, m.textPanelHeight(), m.palette)
+		panelControls := lipgloss.NewStyle().
+			Foreground(m.palette.Controls).
+			Render("[↑/↓ PgUp/PgDn] scroll  [a] auto-scroll  [m] message  [n] names  [S] stats  [?] keys  [esc] close")
+		if m.searchTyping {
+			panelControls = controls
+		}
+		sections = append(sections, "", panel, "", panelControls)
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
 	sections = append(sections, "", activeInstruments)
 	sections = append(sections, "", vuMeters)
+	if viz := m.renderViz(); viz != "" {
+		sections = append(sections, viz)
+	}
+	if m.showKeyboard {
+		sections = append(sections, RenderPianoKeyboard(m.notes.notes, mutedChannels, m.width, m.palette))
+	}
+	if m.showHarmony {
+		sections = append(sections, RenderHarmony(m.harmony, m.rollRow, m.width, m.palette))
+	}
 	sections = append(sections, pattern, "", controls)
 
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
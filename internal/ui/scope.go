@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// VizMode selects the visualizer panel shown between the VU meters and the pattern view
+type VizMode int
+
+const (
+	VizNone VizMode = iota
+	VizScope
+	VizSpectrum
+	VizVectorscope
+	vizModeCount
+)
+
+// next cycles through the visualizer modes, wrapping back to none
+func (v VizMode) next() VizMode {
+	return (v + 1) % vizModeCount
+}
+
+// scopeRows is the height of each channel's trace in character rows
+const scopeRows = 3
+
+// scopeWindow is how many frames the oscilloscope shows (~23ms at 44.1kHz)
+const scopeWindow = 1024
+
+// RenderOscilloscope draws the left and right output as stacked braille traces
+// Samples are expected in [-1, 1]; the panel is 2*scopeRows lines tall
+func RenderOscilloscope(left, right []float32, width int, palette ColorPalette) string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	leftStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	rightStyle := lipgloss.NewStyle().Foreground(palette.Instrument)
+
+	// Match the VU meter label column ("VU▲ │")
+	cols := width - 6
+	if cols < 8 {
+		cols = 8
+	}
+
+	var lines []string
+	for _, trace := range []struct {
+		name    string
+		samples []float32
+		style   lipgloss.Style
+	}{
+		{"L", left, leftStyle},
+		{"R", right, rightStyle},
+	} {
+		canvas := newBrailleCanvas(cols, scopeRows)
+		drawTrace(canvas, trace.samples)
+		for row, line := range canvas.lines() {
+			label := "    "
+			if row == scopeRows/2 {
+				label = fmt.Sprintf("%-4s", "Sc"+trace.name)
+			}
+			lines = append(lines, labelStyle.Render(label)+" │"+trace.style.Render(line))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// drawTrace plots samples across the canvas width, joining neighbouring points
+// with vertical runs so steep edges stay continuous
+func drawTrace(canvas *brailleCanvas, samples []float32) {
+	w, h := canvas.width(), canvas.height()
+	if len(samples) == 0 {
+		// Flat line at zero
+		for x := 0; x < w; x++ {
+			canvas.set(x, h/2)
+		}
+		return
+	}
+
+	toY := func(v float32) int {
+		if v > 1 {
+			v = 1
+		}
+		if v < -1 {
+			v = -1
+		}
+		return int((1 - (v+1)/2) * float32(h-1))
+	}
+
+	prevY := toY(samples[0])
+	for x := 0; x < w; x++ {
+		idx := x * len(samples) / w
+		y := toY(samples[idx])
+		canvas.vline(x, prevY, y)
+		prevY = y
+	}
+}
@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// searchPanelWidth is the rendered width of the results panel (including its left border)
+	searchPanelWidth = 20
+
+	// searchMaxHits stops runaway searches (e.g. "any note") on long songs
+	searchMaxHits = 10000
+)
+
+var (
+	notePattern = regexp.MustCompile(`^([A-G])([-#]?)([0-9]?)$`)
+	// Bare notes need an accidental or octave so single letters stay effects ("D" is volume slide)
+	bareNotePattern = regexp.MustCompile(`^[A-G]([-#][0-9]?|[0-9])$`)
+	effectPattern   = regexp.MustCompile(`^([0-9A-Z])([0-9A-F]{2}|XX|\?\?)?$`)
+	hexPattern      = regexp.MustCompile(`^[0-9A-F]{1,2}$`)
+)
+
+// searchQuery matches pattern cells by their native text; empty fields match anything
+type searchQuery struct {
+	note       string // "C-5", a note name without octave ("C#"), or a special note ("===")
+	instrument string // Two hex digits as shown in the cell
+	volume     string // Volume column as shown, e.g. "v40"
+	effect     string // Effect command character in the module's own notation
+	parameter  string // Effect parameter, two hex digits
+}
+
+// searchHit is one matching cell in song order
+type searchHit struct {
+	Order   int
+	Pattern int
+	Row     int
+	Channel int
+}
+
+// searchResults is a finished search and the selected hit
+type searchResults struct {
+	query    string
+	hits     []searchHit
+	selected int
+}
+
+// parseSearchQuery understands notes ("C-5", "C#", "==="), effects ("A0F", "D", "fx Dxx"),
+// instruments ("i03", "instrument 03") and volume columns ("vol v40"), in any combination
+// Filler words make natural queries work: "C-5 with instrument 03"
+func parseSearchQuery(input string) (searchQuery, error) {
+	var q searchQuery
+	tokens := strings.Fields(input)
+	if len(tokens) == 0 {
+		return q, fmt.Errorf("empty search")
+	}
+
+	next := func(i *int, what string) (string, error) {
+		*i++
+		if *i >= len(tokens) {
+			return "", fmt.Errorf("%s needs a value", what)
+		}
+		return tokens[*i], nil
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		var err error
+		switch lower := strings.ToLower(tok); {
+		case lower == "with" || lower == "and" || lower == "+" || lower == "on":
+			continue
+		case lower == "note":
+			if tok, err = next(&i, "note"); err == nil {
+				err = q.setNote(tok)
+			}
+		case lower == "instrument" || lower == "inst" || lower == "ins" || lower == "i":
+			if tok, err = next(&i, "instrument"); err == nil {
+				err = q.setInstrument(tok)
+			}
+		case lower == "effect" || lower == "fx":
+			if tok, err = next(&i, "effect"); err == nil {
+				err = q.setEffect(tok)
+			}
+		case lower == "volume" || lower == "vol":
+			if tok, err = next(&i, "volume"); err == nil {
+				err = q.setVolume(tok)
+			}
+		case lower == "===" || lower == "^^^" || lower == "~~~":
+			err = q.setNote(tok)
+		case len(lower) > 1 && lower[0] == 'i' && hexPattern.MatchString(strings.ToUpper(tok[1:])):
+			err = q.setInstrument(tok[1:])
+		default:
+			// Bare tokens: "A-4" is a note, "A04" and "A" are effects
+			if bareNotePattern.MatchString(strings.ToUpper(tok)) && q.setNote(tok) == nil {
+				continue
+			}
+			if q.setEffect(tok) == nil {
+				continue
+			}
+			err = fmt.Errorf("don't know how to search for %q", tok)
+		}
+		if err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
+
+func (q *searchQuery) setNote(tok string) error {
+	switch strings.ToLower(tok) {
+	case "===", "off":
+		q.note = "==="
+		return nil
+	case "^^^", "cut":
+		q.note = "^^^"
+		return nil
+	case "~~~", "fade":
+		q.note = "~~~"
+		return nil
+	}
+	m := notePattern.FindStringSubmatch(strings.ToUpper(tok))
+	if m == nil {
+		return fmt.Errorf("%q is not a note", tok)
+	}
+	accidental := m[2]
+	if accidental == "" {
+		accidental = "-"
+	}
+	q.note = m[1] + accidental + m[3]
+	return nil
+}
+
+func (q *searchQuery) setInstrument(tok string) error {
+	n, err := strconv.ParseUint(tok, 16, 8)
+	if err != nil || n == 0 {
+		return fmt.Errorf("%q is not an instrument number (hex, 01-FF)", tok)
+	}
+	q.instrument = fmt.Sprintf("%02X", n)
+	return nil
+}
+
+func (q *searchQuery) setEffect(tok string) error {
+	m := effectPattern.FindStringSubmatch(strings.ToUpper(tok))
+	if m == nil {
+		return fmt.Errorf("%q is not an effect", tok)
+	}
+	q.effect = m[1]
+	q.parameter = ""
+	if m[2] != "XX" && m[2] != "??" {
+		q.parameter = m[2]
+	}
+	return nil
+}
+
+func (q *searchQuery) setVolume(tok string) error {
+	if len(tok) != 3 || !hexPattern.MatchString(strings.ToUpper(tok[1:])) {
+		return fmt.Errorf("%q is not a volume column entry like v40", tok)
+	}
+	q.volume = strings.ToLower(tok[:1]) + strings.ToUpper(tok[1:])
+	return nil
+}
+
+// matches reports whether a cell satisfies every field of the query
+func (q searchQuery) matches(cell player.PatternCell) bool {
+	text := cellText(cell, fullCellWidth)
+	field := func(at int, want string) bool {
+		return want == "" || text[at:at+len(want)] == want
+	}
+	return field(player.CellNoteAt, q.note) &&
+		field(player.CellInstrumentAt, q.instrument) &&
+		field(player.CellVolumeAt, q.volume) &&
+		field(player.CellEffectAt, q.effect) &&
+		(q.parameter == "" || field(player.CellParameterAt, q.parameter))
+}
+
+// searchSong scans the whole order list (every subsong), so repeated patterns report each play
+func searchSong(mod *player.Module, orders []int, q searchQuery) []searchHit {
+	var hits []searchHit
+	for order, pattern := range orders {
+		if pattern == player.OrderSkip || pattern == player.OrderEnd {
+			continue // Later subsongs sit past "---" markers
+		}
+		for row, data := range mod.GetCachedPattern(pattern).Rows {
+			for ch, cell := range data.Channels {
+				if !q.matches(cell) {
+					continue
+				}
+				hits = append(hits, searchHit{Order: order, Pattern: pattern, Row: row, Channel: ch})
+				if len(hits) >= searchMaxHits {
+					return hits
+				}
+			}
+		}
+	}
+	return hits
+}
+
+// RenderSearchResults renders the hit list beside the pattern view, keeping the selection in view
+func RenderSearchResults(results *searchResults, height int, palette ColorPalette) string {
+	if height < 4 {
+		height = 4
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	hitStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	labelStyle := lipgloss.NewStyle().Foreground(palette.RowNumber)
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(palette.CurrentRow).
+		Background(palette.CurrentRowBg)
+
+	inner := searchPanelWidth - 2
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(fitWidth("Find "+results.query, inner)))
+	count := fmt.Sprintf("%d hits", len(results.hits))
+	if len(results.hits) >= searchMaxHits {
+		count = fmt.Sprintf("%d+ hits", searchMaxHits)
+	}
+	if len(results.hits) > 0 {
+		count = fmt.Sprintf("%d/%s", results.selected+1, count)
+	}
+	lines = append(lines, labelStyle.Render(fitWidth(count, inner)))
+	lines = append(lines, labelStyle.Render(fitWidth(" Ord Pat  Row Ch", inner)))
+
+	visible := height - 3
+	start := results.selected - visible/2
+	if start > len(results.hits)-visible {
+		start = len(results.hits) - visible
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	for i := start; i < start+visible; i++ {
+		if i >= len(results.hits) {
+			lines = append(lines, strings.Repeat(" ", inner))
+			continue
+		}
+		hit := results.hits[i]
+		text := fitWidth(fmt.Sprintf(" %03d %03d %04X %2d", hit.Order, hit.Pattern, hit.Row, hit.Channel+1), inner)
+		if i == results.selected {
+			lines = append(lines, selectedStyle.Render(text))
+		} else {
+			lines = append(lines, hitStyle.Render(text))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(palette.Border).
+		PaddingLeft(1).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,76 @@
+package ui
+
+import "testing"
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  searchQuery
+	}{
+		// Notes
+		{"C-5", searchQuery{note: "C-5"}},
+		{"c#4", searchQuery{note: "C#4"}},
+		{"C#", searchQuery{note: "C#"}},
+		{"C5", searchQuery{note: "C-5"}},
+		{"note C", searchQuery{note: "C-"}},
+		{"===", searchQuery{note: "==="}},
+		{"note off", searchQuery{note: "==="}},
+		{"note cut", searchQuery{note: "^^^"}},
+		{"~~~", searchQuery{note: "~~~"}},
+
+		// Instruments (hex, as the cell shows them)
+		{"i03", searchQuery{instrument: "03"}},
+		{"i1F", searchQuery{instrument: "1F"}},
+		{"instrument 3", searchQuery{instrument: "03"}},
+		{"ins a", searchQuery{instrument: "0A"}},
+
+		// Effects: a single letter is an effect, not a note
+		{"D", searchQuery{effect: "D"}},
+		{"A0F", searchQuery{effect: "A", parameter: "0F"}},
+		{"fx Dxx", searchQuery{effect: "D"}},
+		{"effect F??", searchQuery{effect: "F"}},
+		{"effect 0", searchQuery{effect: "0"}},
+
+		// Volume column
+		{"vol v40", searchQuery{volume: "v40"}},
+		{"volume P20", searchQuery{volume: "p20"}},
+
+		// Combinations with filler words
+		{"C-5 with instrument 03", searchQuery{note: "C-5", instrument: "03"}},
+		{"A-4 i02 and A0F", searchQuery{note: "A-4", instrument: "02", effect: "A", parameter: "0F"}},
+		{"note E-3 on vol v20 + fx Bxx", searchQuery{note: "E-3", volume: "v20", effect: "B"}},
+	}
+	for _, tt := range tests {
+		got, err := parseSearchQuery(tt.input)
+		if err != nil {
+			t.Errorf("parseSearchQuery(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"note",         // Missing value
+		"instrument",   // Missing value
+		"note H-5",     // Not a note
+		"instrument 0", // Instruments start at 01
+		"i100",         // More than two hex digits
+		"instrument zz",
+		"fx A0",  // Parameter needs two digits
+		"fx A0G", // Parameter isn't hex
+		"vol 40", // Volume needs its command letter
+		"vol v4",
+		"hello",
+		"C-5 @",
+	} {
+		if q, err := parseSearchQuery(input); err == nil {
+			t.Errorf("parseSearchQuery(%q) = %+v, want an error", input, q)
+		}
+	}
+}
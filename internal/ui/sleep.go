@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+// DefaultFadeMs is the fade-out length for the sleep timer and the loop/duration limits
+const DefaultFadeMs = 20000
+
+// sleepPresets are the sleep timer lengths the z key cycles through
+var sleepPresets = []time.Duration{15 * time.Minute, 30 * time.Minute, 45 * time.Minute, 60 * time.Minute, 90 * time.Minute}
+
+// nextSleepPreset picks the first preset longer than the time left (plus a minute, so a just-set
+// timer moves on), or 0 to turn the timer off after the longest
+func nextSleepPreset(left time.Duration) time.Duration {
+	for _, preset := range sleepPresets {
+		if preset > left+time.Minute {
+			return preset
+		}
+	}
+	return 0
+}
+
+// formatStopStatus summarises the pending automatic stops for the header, "" when there are none
+func formatStopStatus(st player.StopStatus, now time.Time) string {
+	var parts []string
+	if st.Fading != player.StopNone {
+		parts = append(parts, "fading ("+st.Fading.String()+")")
+	}
+	if !st.SleepAt.IsZero() {
+		parts = append(parts, "sleep "+formatTime(max(0, st.SleepAt.Sub(now)).Seconds()))
+	}
+	if st.AfterCurrent {
+		parts = append(parts, "after song")
+	}
+	switch {
+	case st.Loops > 0:
+		parts = append(parts, fmt.Sprintf("loop %d/%d", min(st.Loop+1, st.Loops), st.Loops))
+	case st.Loops < 0:
+		parts = append(parts, fmt.Sprintf("loop %d/∞", st.Loop+1))
+	}
+	if st.MaxLeft > 0 {
+		parts = append(parts, "max "+formatTime(st.MaxLeft.Seconds()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cycleSleepTimer moves the sleep timer to the next preset, or turns it off
+func (m *PlayerModel) cycleSleepTimer() {
+	left := time.Duration(0)
+	if at := m.sleepDeadline(); !at.IsZero() {
+		left = time.Until(at)
+	}
+	m.sleepAt = time.Time{}
+	if preset := nextSleepPreset(left); preset > 0 {
+		m.sleepAt = time.Now().Add(preset)
+	}
+	if m.player != nil {
+		m.player.SetSleepTimer(m.sleepAt)
+	}
+}
+
+// sleepDeadline is when the sleep timer ends playback, zero when it is off
+// The app carries it over when another module is opened.
+func (m *PlayerModel) sleepDeadline() time.Time {
+	if m.player != nil {
+		return m.player.SleepTimer()
+	}
+	return m.sleepAt
+}
+
+// stopStatus is the header's summary of the pending automatic stops
+func (m *PlayerModel) stopStatus() string {
+	if m.player == nil {
+		return ""
+	}
+	return formatStopStatus(m.player.StopStatus(), time.Now())
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+// StdinName is the CLI argument that selects standard input as the module source
+const StdinName = "-"
+
+var errNoSource = errors.New("no module source")
+
+// ModuleSource describes where the player loads a module from
+// The zero value means "no module" (the browser is shown instead)
+type ModuleSource struct {
+	Name string // Display name used when the module has no title
+	Path string // Filesystem path, empty for non-file sources
+	open func() (*player.Module, error)
+}
+
+// FileSource loads a module from a path on disk
+func FileSource(filename string) ModuleSource {
+	return ModuleSource{
+		Name: filename,
+		Path: filename,
+		open: func() (*player.Module, error) {
+			return player.LoadModule(filename)
+		},
+	}
+}
+
+// ReaderSource loads a module by reading r to EOF (e.g. os.Stdin)
+func ReaderSource(name string, r io.Reader) ModuleSource {
+	return ModuleSource{
+		Name: name,
+		open: func() (*player.Module, error) {
+			return player.LoadModuleFromReader(r)
+		},
+	}
+}
+
+// FSSource loads a module named name from fsys (e.g. an embed.FS of demo tunes)
+func FSSource(fsys fs.FS, name string) ModuleSource {
+	return ModuleSource{
+		Name: path.Base(name),
+		open: func() (*player.Module, error) {
+			return player.LoadModuleFS(fsys, name)
+		},
+	}
+}
+
+// SourceFromArg maps a CLI argument to a source: "-" reads stdin, anything else is a path
+func SourceFromArg(arg string) ModuleSource {
+	if arg == StdinName {
+		return ReaderSource("<stdin>", os.Stdin)
+	}
+	return FileSource(arg)
+}
+
+// IsZero reports whether the source points at nothing
+func (s ModuleSource) IsZero() bool {
+	return s.open == nil
+}
+
+// Load opens the module described by the source
+func (s ModuleSource) Load() (*player.Module, error) {
+	if s.open == nil {
+		return nil, errNoSource
+	}
+	return s.open()
+}
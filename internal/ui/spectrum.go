@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// fftSize is the analysis window in frames (~46ms at 44.1kHz)
+	fftSize = 2048
+
+	// spectrumRows is the height of the bars in character rows
+	spectrumRows = 4
+
+	// Frequency range covered by the log-spaced bands
+	spectrumMinHz = 40.0
+	spectrumMaxHz = 16000.0
+
+	// Levels are mapped from spectrumFloorDB..0 dBFS onto 0..1
+	spectrumFloorDB = -60.0
+
+	// DefaultSpectrumBands is used when the config doesn't set a band count
+	DefaultSpectrumBands = 32
+
+	outputSampleRate = 44100.0
+)
+
+// Smoothing and peak-hold behaviour, per UI tick (~60 per second)
+const (
+	spectrumAttack    = 0.6  // Fraction of a rise applied per tick
+	spectrumRelease   = 0.15 // Fraction of a fall applied per tick
+	spectrumPeakHold  = 30   // Ticks a peak cap stays put before falling
+	spectrumPeakFall  = 0.02 // Level units a released cap falls per tick
+	spectrumMinBands  = 4
+	spectrumMaxBands  = 128
+	spectrumBandWidth = 2 // Columns per band (bar + gap) at full size
+)
+
+// SpectrumAnalyzer turns output audio into smoothed log-frequency band levels
+type SpectrumAnalyzer struct {
+	bands  int
+	levels []float64 // Smoothed level per band (0..1)
+	peaks  []float64 // Peak-hold cap per band (0..1)
+	hold   []int     // Ticks left before each cap starts falling
+
+	window []float64
+	re, im []float64
+	edges  []int // FFT bin boundaries, len(bands)+1
+}
+
+// NewSpectrumAnalyzer creates an analyzer with the given number of bands
+func NewSpectrumAnalyzer(bands int) *SpectrumAnalyzer {
+	a := &SpectrumAnalyzer{
+		window: hannWindow(fftSize),
+		re:     make([]float64, fftSize),
+		im:     make([]float64, fftSize),
+	}
+	a.SetBands(bands)
+	return a
+}
+
+// Bands returns the current band count
+func (a *SpectrumAnalyzer) Bands() int {
+	return a.bands
+}
+
+// SetBands changes the band count, resetting smoothing state
+func (a *SpectrumAnalyzer) SetBands(bands int) {
+	if bands < spectrumMinBands {
+		bands = spectrumMinBands
+	}
+	if bands > spectrumMaxBands {
+		bands = spectrumMaxBands
+	}
+	if bands == a.bands && a.levels != nil {
+		return
+	}
+
+	a.bands = bands
+	a.levels = make([]float64, bands)
+	a.peaks = make([]float64, bands)
+	a.hold = make([]int, bands)
+
+	// Log-spaced band edges, each band at least one bin wide
+	binHz := outputSampleRate / fftSize
+	a.edges = make([]int, bands+1)
+	ratio := math.Log(spectrumMaxHz / spectrumMinHz)
+	for i := 0; i <= bands; i++ {
+		hz := spectrumMinHz * math.Exp(ratio*float64(i)/float64(bands))
+		bin := int(math.Round(hz / binHz))
+		if i > 0 && bin <= a.edges[i-1] {
+			bin = a.edges[i-1] + 1
+		}
+		if bin > fftSize/2 {
+			bin = fftSize / 2
+		}
+		a.edges[i] = bin
+	}
+}
+
+// Update analyses the latest window of stereo audio (mixed to mono)
+// Only the last fftSize frames are used; shorter input is zero-padded
+func (a *SpectrumAnalyzer) Update(left, right []float32) {
+	n := len(left)
+	if len(right) < n {
+		n = len(right)
+	}
+	offset := n - fftSize
+	for i := 0; i < fftSize; i++ {
+		src := offset + i
+		var v float64
+		if src >= 0 && src < n {
+			v = (float64(left[src]) + float64(right[src])) / 2
+		}
+		a.re[i] = v * a.window[i]
+		a.im[i] = 0
+	}
+
+	fft(a.re, a.im)
+
+	// Normalise so a full-scale sine reads ~0 dBFS (Hann window has 0.5 coherent gain)
+	norm := 2.0 / (fftSize * 0.5)
+
+	for b := 0; b < a.bands; b++ {
+		lo, hi := a.edges[b], a.edges[b+1]
+		if hi <= lo {
+			hi = lo + 1
+		}
+		// Peak magnitude in the band keeps narrow tones visible in wide bands
+		var mag float64
+		for k := lo; k < hi && k < fftSize/2; k++ {
+			m := math.Hypot(a.re[k], a.im[k]) * norm
+			if m > mag {
+				mag = m
+			}
+		}
+
+		level := 0.0
+		if mag > 0 {
+			db := 20 * math.Log10(mag)
+			level = (db - spectrumFloorDB) / -spectrumFloorDB
+		}
+		level = math.Max(0, math.Min(1, level))
+
+		// Smoothing: fast attack, slow release
+		if level > a.levels[b] {
+			a.levels[b] += (level - a.levels[b]) * spectrumAttack
+		} else {
+			a.levels[b] += (level - a.levels[b]) * spectrumRelease
+		}
+
+		// Peak hold caps
+		if a.levels[b] >= a.peaks[b] {
+			a.peaks[b] = a.levels[b]
+			a.hold[b] = spectrumPeakHold
+		} else if a.hold[b] > 0 {
+			a.hold[b]--
+		} else {
+			a.peaks[b] = math.Max(a.levels[b], a.peaks[b]-spectrumPeakFall)
+		}
+	}
+}
+
+// RenderSpectrum draws the analyzer's bands as bars with peak caps
+// Bars are narrowed, then bands merged, so the panel never exceeds width
+func RenderSpectrum(a *SpectrumAnalyzer, width int, palette ColorPalette) string {
+	lowStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	highStyle := lipgloss.NewStyle().Foreground(palette.InfoValue).Bold(true)
+	topStyle := lipgloss.NewStyle().Foreground(palette.CurrentRow).Bold(true)
+	capStyle := lipgloss.NewStyle().Foreground(palette.Title)
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+
+	// Same label column as the VU meters ("VU▲ │")
+	available := width - 6
+	if available < spectrumMinBands {
+		available = spectrumMinBands
+	}
+
+	levels, peaks := a.levels, a.peaks
+	barWidth := spectrumBandWidth
+	if len(levels)*barWidth > available {
+		barWidth = 1
+	}
+	if len(levels) > available {
+		levels = mergeBands(levels, available)
+		peaks = mergeBands(peaks, available)
+	}
+
+	eighths := []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	labels := []string{"SP▲", "", "", "SP▼"}
+
+	var rows []string
+	for row := 0; row < spectrumRows; row++ {
+		var b strings.Builder
+		b.WriteString(labelStyle.Render(padRight(labels[row], 4)))
+		b.WriteString(" │")
+
+		// This row covers levels (rowBase, rowBase+1/spectrumRows]
+		rowFromBottom := spectrumRows - 1 - row
+		style := lowStyle
+		if rowFromBottom == spectrumRows-1 {
+			style = topStyle
+		} else if rowFromBottom >= spectrumRows/2 {
+			style = highStyle
+		}
+
+		for i, level := range levels {
+			fill := level*spectrumRows - float64(rowFromBottom)
+			cell := " "
+			cellStyle := style
+			if fill >= 1 {
+				cell = "█"
+			} else if fill > 0 {
+				cell = eighths[int(fill*8)]
+			}
+
+			// Peak cap sits in the row that contains the held peak, above the bar
+			capFill := peaks[i]*spectrumRows - float64(rowFromBottom)
+			if cell == " " && capFill > 0 && capFill <= 1 && peaks[i] > level+0.01 {
+				cell = "▔"
+				cellStyle = capStyle
+			}
+
+			b.WriteString(cellStyle.Render(cell))
+			if barWidth > 1 {
+				b.WriteString(" ")
+			}
+		}
+		rows = append(rows, b.String())
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// mergeBands reduces values to n entries, keeping the maximum of each group
+func mergeBands(values []float64, n int) []float64 {
+	out := make([]float64, n)
+	for i, v := range values {
+		j := i * n / len(values)
+		if v > out[j] {
+			out[j] = v
+		}
+	}
+	return out
+}
+
+func padRight(s string, width int) string {
+	if pad := width - lipgloss.Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slimewell/GoMod/internal/player"
+)
+
+// statsBarWidth is the longest bar in the effect histogram
+const statsBarWidth = 30
+
+// StatsLines lays out song statistics as plain text, shared by the overlay and `gomod stats`
+func StatsLines(stats player.SongStats) []string {
+	var lines []string
+	add := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	add("── Song ──")
+	add("Patterns:        %d (%d referenced, %d unused)", stats.NumPatterns, len(stats.ReferencedPatterns), len(stats.UnusedPatterns))
+	add("Order list rows: %d", stats.OrderRows)
+	add("Notes:           %d", stats.TotalNotes)
+	add("Notes per row:   %.2f", stats.NotesPerRow)
+	if len(stats.UnusedPatterns) > 0 {
+		add("Unused patterns: %s", joinInts(stats.UnusedPatterns, "%03d"))
+	}
+
+	add("")
+	add("── Channels ──")
+	add("  Ch   Notes  Range")
+	for _, ch := range stats.Channels {
+		if ch.Notes == 0 {
+			add("  %2d  %6d  -", ch.Channel, ch.Notes)
+			continue
+		}
+		add("  %2d  %6d  %s .. %s", ch.Channel, ch.Notes, ch.Lowest, ch.Highest)
+	}
+
+	add("")
+	add("── Instruments ──")
+	add("  ##    Uses  Channels        Name")
+	for _, inst := range stats.Instruments {
+		add("  %02X  %6d  %-14s  %s", inst.Instrument, inst.Uses, joinInts(inst.Channels, "%d"), cleanLine(inst.Name))
+	}
+	if len(stats.UnusedInstruments) > 0 {
+		add("Unused: %s", joinInts(stats.UnusedInstruments, "%02X"))
+	}
+
+	add("")
+	add("── Effects ──")
+	if len(stats.Effects) == 0 {
+		add("  (none)")
+	}
+	maxCount := 1
+	for _, e := range stats.Effects {
+		maxCount = max(maxCount, e.Count)
+	}
+	for _, e := range stats.Effects {
+		bar := strings.Repeat("█", max(1, e.Count*statsBarWidth/maxCount))
+		add("  %s  %6d  %s", e.Effect, e.Count, bar)
+	}
+
+	return lines
+}
+
+// joinInts formats numbers with a shared verb, comma separated
+func joinInts(values []int, verb string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf(verb, v)
+	}
+	return strings.Join(parts, ",")
+}
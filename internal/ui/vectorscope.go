@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// vectorRows × vectorCols cells gives a 24×24 dot, roughly square plot
+	vectorRows = 6
+	vectorCols = 12
+
+	// correlationSmoothing is the fraction of a change applied per UI tick
+	correlationSmoothing = 0.2
+)
+
+// stereoCorrelation returns the phase correlation of left and right in [-1, 1]
+// +1 is mono, 0 is unrelated (wide) material, -1 is out of phase. Silence reads as +1.
+func stereoCorrelation(left, right []float32) float64 {
+	var lr, ll, rr float64
+	for i := range left {
+		if i >= len(right) {
+			break
+		}
+		l, r := float64(left[i]), float64(right[i])
+		lr += l * r
+		ll += l * l
+		rr += r * r
+	}
+	if ll < 1e-9 || rr < 1e-9 {
+		return 1
+	}
+	return lr / math.Sqrt(ll*rr)
+}
+
+// smoothCorrelation eases the displayed correlation towards the latest reading
+func smoothCorrelation(current, target float64) float64 {
+	return current + (target-current)*correlationSmoothing
+}
+
+// RenderVectorscope draws a mid/side goniometer of the output next to a correlation meter
+// Mono material is a vertical line, wide material spreads sideways, out-of-phase leans flat
+func RenderVectorscope(left, right []float32, correlation float64, stereoSep int, width int, palette ColorPalette) string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.InfoLabel)
+	valueStyle := lipgloss.NewStyle().Foreground(palette.InfoValue)
+	plotStyle := lipgloss.NewStyle().Foreground(palette.Note)
+	axisStyle := lipgloss.NewStyle().Foreground(palette.Border)
+	clip := lipgloss.NewStyle().MaxWidth(width)
+
+	canvas := newBrailleCanvas(vectorCols, vectorRows)
+	w, h := canvas.width(), canvas.height()
+
+	// Axes: M (vertical) and S (horizontal) through the centre
+	axes := newBrailleCanvas(vectorCols, vectorRows)
+	for y := 0; y < h; y += 2 {
+		axes.set(w/2, y)
+	}
+	for x := 0; x < w; x += 2 {
+		axes.set(x, h/2)
+	}
+
+	for i := range left {
+		if i >= len(right) {
+			break
+		}
+		l, r := float64(left[i]), float64(right[i])
+		mid := (l + r) / 2
+		side := (r - l) / 2
+		x := int(math.Round((side + 1) / 2 * float64(w-1)))
+		y := int(math.Round((1 - (mid+1)/2) * float64(h-1)))
+		canvas.set(x, y)
+	}
+
+	plotLines := canvas.lines()
+	axisLines := axes.lines()
+
+	meter := renderCorrelationMeter(correlation, width-6-vectorCols-4, palette)
+
+	var lines []string
+	for row := 0; row < vectorRows; row++ {
+		label := "    "
+		if row == vectorRows/2 {
+			label = "M/S "
+		}
+
+		// Overlay the plot on the axes cell by cell; plotted dots win
+		plot := []rune(plotLines[row])
+		axis := []rune(axisLines[row])
+		var b strings.Builder
+		for col := range plot {
+			if plot[col] != 0x2800 {
+				b.WriteString(plotStyle.Render(string(plot[col] | axis[col])))
+			} else {
+				b.WriteString(axisStyle.Render(string(axis[col])))
+			}
+		}
+
+		var right string
+		switch row {
+		case 1:
+			right = labelStyle.Render("Correlation ") + valueStyle.Render(fmt.Sprintf("%+.2f", correlation))
+		case 2:
+			right = meter
+		case 3:
+			right = labelStyle.Render("-1 out of phase      0 wide      +1 mono")
+		case 4:
+			right = labelStyle.Render("Stereo separation ") + valueStyle.Render(fmt.Sprintf("%d%%", stereoSep))
+		}
+
+		// The labels have a fixed width, so clip rather than wrap on narrow terminals
+		lines = append(lines, clip.Render(labelStyle.Render(label)+" │"+b.String()+"  "+right))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderCorrelationMeter draws a -1..+1 bar with a marker at the current correlation
+func renderCorrelationMeter(correlation float64, width int, palette ColorPalette) string {
+	if width > 40 {
+		width = 40
+	}
+	if width < 11 {
+		width = 11
+	}
+	// Odd width so 0 has its own centre cell
+	if width%2 == 0 {
+		width--
+	}
+
+	trackStyle := lipgloss.NewStyle().Foreground(palette.Border)
+	goodStyle := lipgloss.NewStyle().Foreground(palette.Note).Bold(true)
+	badStyle := lipgloss.NewStyle().Foreground(palette.CurrentRow).Background(palette.CurrentRowBg).Bold(true)
+
+	pos := int(math.Round((correlation + 1) / 2 * float64(width-1)))
+	center := width / 2
+
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		switch {
+		case i == pos:
+			// Negative correlation means mono playback will cancel: flag it
+			if correlation < 0 {
+				b.WriteString(badStyle.Render("●"))
+			} else {
+				b.WriteString(goodStyle.Render("●"))
+			}
+		case i == center:
+			b.WriteString(trackStyle.Render("┼"))
+		default:
+			b.WriteString(trackStyle.Render("─"))
+		}
+	}
+	return trackStyle.Render("[") + b.String() + trackStyle.Render("]")
+}
@@ -8,8 +8,8 @@ import (
 )
 
 // RenderVUMeters creates a 3-row tall vertical bar per channel (fills bottom-to-top)
-// Muted channels are dimmed/grayed out
-func RenderVUMeters(volumes []float64, mutedChannels []bool, width int, palette ColorPalette) string {
+// Muted channels are dimmed/grayed out; peaks (may be nil) adds a peak-hold marker above the bar
+func RenderVUMeters(volumes, peaks []float64, mutedChannels []bool, cellWidth int, width int, palette ColorPalette) string {
 	numChannels := len(volumes)
 	if numChannels == 0 {
 		return ""
@@ -38,24 +38,10 @@ func RenderVUMeters(volumes []float64, mutedChannels []bool, width int, palette
 			// Check if this channel is muted
 			isMuted := chIdx < len(mutedChannels) && mutedChannels[chIdx]
 
-			// Apply perceptual scaling
-			if vol < 0 {
-				vol = 0
-			}
-			if vol > 1 {
-				vol = 1
-			}
-
-			// Perceptual curve
-			perceived := vol * vol
-			if perceived < 0.1 {
-				perceived = perceived * 1.5
-			}
-
-			// Map to 0-9 level range (3 rows × 3 levels = 9 total levels)
-			level := int(perceived * 9.0)
-			if level > 9 {
-				level = 9
+			level := vuLevel(vol)
+			peakLevel := 0
+			if chIdx < len(peaks) {
+				peakLevel = vuLevel(peaks[chIdx])
 			}
 
 			// Determine which rows should be lit based on level
@@ -155,11 +141,20 @@ func RenderVUMeters(volumes []float64, mutedChannels []bool, width int, palette
 				}
 			}
 
-			// Column width: 14 chars to match pattern
+			// Peak-hold marker on the row the held peak falls in, above the bar
+			if char == " " && peakLevel > level && vuRowOf(peakLevel) == rowIdx {
+				char = "▔"
+				if !isMuted {
+					style = peakStyle
+				}
+			}
+
+			// Column width matches the pattern cells, bar centred
+			padLeft := max(0, (cellWidth-1)/2)
 			cellContent := fmt.Sprintf("%s%s%s",
-				strings.Repeat(" ", 6),
+				strings.Repeat(" ", padLeft),
 				style.Render(char),
-				strings.Repeat(" ", 7),
+				strings.Repeat(" ", max(0, cellWidth-1-padLeft)),
 			)
 
 			rows[rowIdx].WriteString(cellContent)
@@ -170,3 +165,25 @@ func RenderVUMeters(volumes []float64, mutedChannels []bool, width int, palette
 	// Join all 3 rows with newlines
 	return rows[0].String() + "\n" + rows[1].String() + "\n" + rows[2].String()
 }
+
+// vuLevel maps a 0-1 volume onto the meter's 0-9 levels (3 rows × 3 levels) along a perceptual curve
+func vuLevel(vol float64) int {
+	vol = max(0, min(1, vol))
+	perceived := vol * vol
+	if perceived < 0.1 {
+		perceived = perceived * 1.5
+	}
+	return min(9, int(perceived*9.0))
+}
+
+// vuRowOf is the meter row (0 top, 2 bottom) a level first lights up
+func vuRowOf(level int) int {
+	switch {
+	case level >= 6:
+		return 0
+	case level >= 3:
+		return 1
+	default:
+		return 2
+	}
+}